@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+	"maganghub-autopresence/internal/config"
+	"maganghub-autopresence/internal/history"
+	"maganghub-autopresence/internal/metrics"
+	"maganghub-autopresence/internal/schedule"
+)
+
+//go:embed dashboard.html
+var dashboardHTML string
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"time": func(t time.Time) string { return t.Local().Format("2006-01-02 15:04:05") },
+}).Parse(dashboardHTML))
+
+// adminServer exposes the daemon's state, metrics and a manual trigger over HTTP
+type adminServer struct {
+	cfg       *config.Config
+	sess      *session
+	scheduler *schedule.Scheduler
+	started   time.Time
+
+	// cookies caches the last cookie check, see cookieCheckTTL
+	cookieMu sync.Mutex
+	cookies  cookieStatus
+}
+
+// cookieCheckTTL is how long a cookie check is reused, so a polling
+// dashboard or scraper does not turn into a stream of Monev requests
+const cookieCheckTTL = 5 * time.Minute
+
+// adminStatus is the body of GET /status
+type adminStatus struct {
+	Started     time.Time      `json:"started"`
+	NextRun     string         `json:"next_run"`
+	Running     bool           `json:"running"`
+	DryRun      bool           `json:"dry_run"`
+	LastRun     *history.Entry `json:"last_run"`
+	LastSuccess *history.Entry `json:"last_success"`
+	Cookies     cookieStatus   `json:"cookies"`
+}
+
+// cookieStatus tells whether the cached session still works
+type cookieStatus struct {
+	// State is "valid", "expired", "none" or "unknown"
+	State   string    `json:"state"`
+	Error   string    `json:"error,omitempty"`
+	Checked time.Time `json:"checked"`
+}
+
+// startAdminServer serves the admin API on cfg.AdminAddr until ctx is done.
+// It refuses to listen beyond localhost without ADMIN_TOKEN
+func startAdminServer(ctx context.Context, cfg *config.Config, sess *session, scheduler *schedule.Scheduler) {
+	if cfg.AdminAddr == "" {
+		return
+	}
+	if !isLoopback(cfg.AdminAddr) && cfg.AdminToken == "" {
+		log.Printf("⚠️  Not starting admin API: %s is not a localhost address and ADMIN_TOKEN is not set", cfg.AdminAddr)
+		return
+	}
+
+	// Carry the last submission over restarts so the gauge is not zero
+	if last, err := runHistory.LastSuccess(); err == nil && last != nil {
+		metrics.LastSuccess.SetTime(last.Time)
+	}
+
+	a := &adminServer{cfg: cfg, sess: sess, scheduler: scheduler, started: time.Now()}
+	srv := &http.Server{
+		Handler:           a.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Listen before returning so a taken port is reported at startup
+	ln, err := net.Listen("tcp", cfg.AdminAddr)
+	if err != nil {
+		log.Printf("❌ Admin API not started: %v", err)
+		return
+	}
+
+	go func() {
+		log.Printf("🖥️  Admin API listening on http://%s", cfg.AdminAddr)
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("❌ Admin API stopped: %v", err)
+		}
+	}()
+
+	context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	})
+}
+
+// handler routes the admin API
+func (a *adminServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", a.handleHealth)
+	mux.Handle("GET /metrics", a.authorize(metrics.Handler()))
+	mux.Handle("GET /status", a.authorize(http.HandlerFunc(a.handleStatus)))
+	mux.Handle("GET /history", a.authorize(http.HandlerFunc(a.handleHistory)))
+	mux.Handle("POST /run", sameOriginOnly(a.authorize(http.HandlerFunc(a.handleRun))))
+	mux.Handle("GET /{$}", a.authorize(http.HandlerFunc(a.handleDashboard)))
+	mux.Handle("POST /login", sameOriginOnly(http.HandlerFunc(a.handleLogin)))
+	return mux
+}
+
+// isLoopback reports whether addr (host:port) only listens on localhost
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// sameOriginOnly refuses requests a browser sends on behalf of another
+// site, so a web page cannot make the user's browser start a submission.
+// Clients such as curl send neither header and pass
+func sameOriginOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("sec-fetch-site") {
+		case "", "same-origin", "none":
+		default:
+			writeAdminJSON(w, http.StatusForbidden, map[string]string{"error": "cross-site request refused"})
+			return
+		}
+		if origin := r.Header.Get("origin"); origin != "" {
+			if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+				writeAdminJSON(w, http.StatusForbidden, map[string]string{"error": "cross-site request refused"})
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// adminCookie holds ADMIN_TOKEN in the browser after signing in on the dashboard
+const adminCookie = "admin_token"
+
+// authorize requires ADMIN_TOKEN, when set, as a bearer token or in the
+// cookie set by POST /login. The token is never read from the URL, where
+// it would end up in browser history and proxy logs
+func (a *adminServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.cfg.AdminToken == "" || a.validToken(requestToken(r)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Ask a browser for the token instead of answering with JSON
+		if r.Method == http.MethodGet && r.URL.Path == "/" {
+			a.renderLogin(w, http.StatusUnauthorized, false)
+			return
+		}
+		writeAdminJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	})
+}
+
+// requestToken returns the bearer token, or else the dashboard cookie
+func requestToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("authorization"), "Bearer "); ok {
+		return token
+	}
+	if cookie, err := r.Cookie(adminCookie); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+func (a *adminServer) validToken(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(a.cfg.AdminToken)) == 1
+}
+
+// handleLogin checks the token typed into the dashboard and keeps it in an
+// HttpOnly cookie that is only sent with requests from this site
+func (a *adminServer) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if a.cfg.AdminToken == "" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	if !a.validToken(r.PostFormValue("token")) {
+		a.renderLogin(w, http.StatusUnauthorized, true)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     adminCookie,
+		Value:    a.cfg.AdminToken,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (a *adminServer) renderLogin(w http.ResponseWriter, status int, failed bool) {
+	w.Header().Set("content-type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := dashboardTemplate.Execute(w, map[string]any{"Login": true, "Failed": failed}); err != nil {
+		log.Printf("Warning: Failed to render dashboard: %v", err)
+	}
+}
+
+func (a *adminServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (a *adminServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, http.StatusOK, a.status(r.Context()))
+}
+
+func (a *adminServer) handleHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := history.Query{From: q.Get("from"), To: q.Get("to"), Limit: 50}
+	if limit, err := strconv.Atoi(q.Get("limit")); err == nil && limit > 0 {
+		query.Limit = limit
+	}
+	for _, outcome := range q["outcome"] {
+		query.Outcomes = append(query.Outcomes, history.Outcome(outcome))
+	}
+
+	entries, err := runHistory.Query(query)
+	if err != nil {
+		writeAdminJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	// Newest first reads better in a browser and with curl | head
+	slices.Reverse(entries)
+	writeAdminJSON(w, http.StatusOK, entries)
+}
+
+func (a *adminServer) handleRun(w http.ResponseWriter, r *http.Request) {
+	err := a.scheduler.RunNow(history.TriggerManual)
+
+	status, result := http.StatusAccepted, "started"
+	switch {
+	case errors.Is(err, schedule.ErrBusy):
+		log.Println("🖥️  Run requested through the admin API, but one is already in progress")
+		status, result = http.StatusConflict, "busy"
+	case err != nil:
+		log.Printf("❌ Run requested through the admin API could not start: %v", err)
+		status, result = http.StatusInternalServerError, "failed"
+	default:
+		log.Println("🖥️  Run requested through the admin API")
+	}
+
+	// The dashboard posts a form and expects to land back on the page,
+	// which shows the result
+	if r.Header.Get("content-type") == "application/x-www-form-urlencoded" {
+		http.Redirect(w, r, "/?run="+result, http.StatusSeeOther)
+		return
+	}
+
+	if err != nil {
+		writeAdminJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeAdminJSON(w, status, map[string]string{"status": result})
+}
+
+func (a *adminServer) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	entries, err := runHistory.Query(history.Query{Limit: 20})
+	if err != nil {
+		log.Printf("Warning: Failed to read run history: %v", err)
+	}
+	slices.Reverse(entries)
+
+	w.Header().Set("content-type", "text/html; charset=utf-8")
+	err = dashboardTemplate.Execute(w, map[string]any{
+		"Status":  a.status(r.Context()),
+		"History": entries,
+		"Run":     r.URL.Query().Get("run"),
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to render dashboard: %v", err)
+	}
+}
+
+// status collects the daemon state; checking the cookies may call the API
+func (a *adminServer) status(ctx context.Context) adminStatus {
+	status := adminStatus{
+		Started: a.started,
+		NextRun: a.scheduler.GetNextRun(),
+		Running: a.scheduler.Busy(),
+		DryRun:  a.cfg.DryRun,
+		Cookies: a.checkCookies(ctx),
+	}
+
+	var err error
+	if status.LastRun, err = runHistory.Last(); err != nil {
+		log.Printf("Warning: Failed to read run history: %v", err)
+	}
+	if status.LastSuccess, err = runHistory.LastSuccess(); err != nil {
+		log.Printf("Warning: Failed to read run history: %v", err)
+	}
+	return status
+}
+
+// checkCookies asks Monev whether the cached session is still accepted,
+// without clearing it when it is not. The answer is reused for
+// cookieCheckTTL
+func (a *adminServer) checkCookies(ctx context.Context) cookieStatus {
+	a.cookieMu.Lock()
+	cached := a.cookies
+	a.cookieMu.Unlock()
+	if time.Since(cached.Checked) < cookieCheckTTL {
+		return cached
+	}
+
+	// The lock is not held during the call, so a slow Monev does not block
+	// other requests; concurrent checks may both probe, which is harmless
+	status := a.probeCookies(ctx)
+	status.Checked = time.Now()
+
+	a.cookieMu.Lock()
+	a.cookies = status
+	a.cookieMu.Unlock()
+	return status
+}
+
+// probeCookies calls GetMe with the cached cookies
+func (a *adminServer) probeCookies(ctx context.Context) cookieStatus {
+	if !a.sess.cookies.HasCookies() {
+		return cookieStatus{State: "none"}
+	}
+
+	client := api.NewClient(a.cfg.MaganghubConfig.MonevURL, a.cfg.APITimeout, a.sess.cookies.Get())
+	_, err := client.GetMe(ctx)
+	switch {
+	case err == nil:
+		return cookieStatus{State: "valid"}
+	case errors.Is(err, api.ErrUnauthorized):
+		return cookieStatus{State: "expired"}
+	default:
+		return cookieStatus{State: "unknown", Error: err.Error()}
+	}
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
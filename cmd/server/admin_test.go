@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"maganghub-autopresence/internal/config"
+	"maganghub-autopresence/internal/cookie_manager"
+	"maganghub-autopresence/internal/history"
+	"maganghub-autopresence/internal/schedule"
+)
+
+const testAdminToken = "s3cret-token"
+
+// newAdminTest serves the admin API with ADMIN_TOKEN set and a scheduler
+// whose job reports each run on the returned channel
+func newAdminTest(t *testing.T) (*httptest.Server, <-chan history.Trigger) {
+	t.Helper()
+	dir := t.TempDir()
+
+	previous := runHistory
+	runHistory = history.NewStore(filepath.Join(dir, "history.jsonl"))
+	t.Cleanup(func() { runHistory = previous })
+
+	runs := make(chan history.Trigger, 1)
+	scheduler := schedule.NewScheduler("0 8 * * 1-5", nil)
+	if err := scheduler.Start(func(trigger history.Trigger) error {
+		runs <- trigger
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(scheduler.Stop)
+
+	cfg := &config.Config{AdminToken: testAdminToken}
+	sess := &session{cookies: cookie_manager.NewCookieManager(filepath.Join(dir, "cookies.json"))}
+	a := &adminServer{cfg: cfg, sess: sess, scheduler: scheduler, started: time.Now()}
+
+	srv := httptest.NewServer(a.handler())
+	t.Cleanup(srv.Close)
+	return srv, runs
+}
+
+// postRun sends POST /run with the given headers and returns the status
+func postRun(t *testing.T, srv *httptest.Server, header map[string]string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/run", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestAdminRunRequiresTheToken(t *testing.T) {
+	srv, runs := newAdminTest(t)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+	}{
+		{"no token", nil},
+		{"bad bearer token", map[string]string{"authorization": "Bearer wrong"}},
+		{"bad cookie", map[string]string{"cookie": adminCookie + "=wrong"}},
+		{"token prefix", map[string]string{"authorization": "Bearer " + testAdminToken[:4]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := postRun(t, srv, tt.header); status != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", status, http.StatusUnauthorized)
+			}
+		})
+	}
+
+	select {
+	case <-runs:
+		t.Error("an unauthorized request started a run")
+	default:
+	}
+}
+
+func TestAdminRunRefusesCrossSiteRequests(t *testing.T) {
+	srv, runs := newAdminTest(t)
+	auth := "Bearer " + testAdminToken
+
+	tests := []struct {
+		name   string
+		header map[string]string
+	}{
+		{"cross-site fetch", map[string]string{"authorization": auth, "sec-fetch-site": "cross-site"}},
+		{"same-site fetch", map[string]string{"authorization": auth, "sec-fetch-site": "same-site"}},
+		{"foreign origin", map[string]string{"authorization": auth, "origin": "https://evil.example"}},
+		{"malformed origin", map[string]string{"authorization": auth, "origin": "://"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := postRun(t, srv, tt.header); status != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", status, http.StatusForbidden)
+			}
+		})
+	}
+
+	select {
+	case <-runs:
+		t.Error("a cross-site request started a run")
+	default:
+	}
+}
+
+func TestAdminRunAcceptsSameOriginRequests(t *testing.T) {
+	auth := "Bearer " + testAdminToken
+
+	tests := []struct {
+		name   string
+		header func(srv *httptest.Server) map[string]string
+	}{
+		{"curl", func(*httptest.Server) map[string]string {
+			return map[string]string{"authorization": auth}
+		}},
+		{"dashboard form", func(srv *httptest.Server) map[string]string {
+			return map[string]string{"cookie": adminCookie + "=" + testAdminToken, "sec-fetch-site": "same-origin", "origin": srv.URL}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, runs := newAdminTest(t)
+			if status := postRun(t, srv, tt.header(srv)); status != http.StatusAccepted {
+				t.Fatalf("status = %d, want %d", status, http.StatusAccepted)
+			}
+			select {
+			case trigger := <-runs:
+				if trigger != history.TriggerManual {
+					t.Errorf("trigger = %q, want %q", trigger, history.TriggerManual)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("the run did not start")
+			}
+		})
+	}
+}
+
+func TestAdminLoginChecksTheToken(t *testing.T) {
+	srv, _ := newAdminTest(t)
+	client := srv.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	resp, err := client.PostForm(srv.URL+"/login", url.Values{"token": {"wrong"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || len(resp.Cookies()) != 0 {
+		t.Errorf("bad token: status %d, cookies %v; want 401 and no cookie", resp.StatusCode, resp.Cookies())
+	}
+
+	resp, err = client.PostForm(srv.URL+"/login", url.Values{"token": {testAdminToken}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	cookies := resp.Cookies()
+	if resp.StatusCode != http.StatusSeeOther || len(cookies) != 1 || cookies[0].Name != adminCookie {
+		t.Fatalf("good token: status %d, cookies %v; want 303 and %s", resp.StatusCode, cookies, adminCookie)
+	}
+	if !cookies[0].HttpOnly || cookies[0].SameSite != http.SameSiteStrictMode {
+		t.Errorf("cookie %+v is not HttpOnly and SameSite=Strict", cookies[0])
+	}
+}
+
+func TestAdminServerNeedsATokenBeyondLocalhost(t *testing.T) {
+	previous := runHistory
+	runHistory = history.NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	t.Cleanup(func() { runHistory = previous })
+
+	// Find a free port, then ask for it on every interface
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startAdminServer(ctx, &config.Config{AdminAddr: net.JoinHostPort("0.0.0.0", port)}, nil, nil)
+
+	if conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", port), time.Second); err == nil {
+		conn.Close()
+		t.Fatal("admin API listens on all interfaces without ADMIN_TOKEN")
+	}
+
+	for addr, want := range map[string]bool{
+		"127.0.0.1:8080": true,
+		"[::1]:8080":     true,
+		"localhost:8080": true,
+		"0.0.0.0:8080":   false,
+		":8080":          false,
+		"10.0.0.5:8080":  false,
+		"127.0.0.1":      false,
+	} {
+		if got := isLoopback(addr); got != want {
+			t.Errorf("isLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+	"maganghub-autopresence/internal/config"
+	"maganghub-autopresence/internal/cookie_manager"
+	"maganghub-autopresence/internal/fakemonev"
+	"maganghub-autopresence/internal/history"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// testDailyLogs covers every day through Fallback, each field long enough
+// to pass the default rules
+var testDailyLogs = map[string]any{
+	"days": map[string]any{
+		"Fallback": map[string][]string{
+			"activity_log":   {strings.Repeat("Mengerjakan task backend bersama tim dan memperbarui dokumentasi. ", 3)},
+			"lesson_learned": {strings.Repeat("Belajar memecah pekerjaan menjadi tugas kecil agar mudah dipantau. ", 3)},
+			"obstacles":      {strings.Repeat("Sempat menunggu akses ke lingkungan staging dari tim infrastruktur. ", 3)},
+		},
+	},
+}
+
+// attendanceTest wires runAttendance to a fake Monev and a fake login
+type attendanceTest struct {
+	cfg    *config.Config
+	sess   *session
+	fake   *fakemonev.Server
+	logins int
+}
+
+func newAttendanceTest(t *testing.T) *attendanceTest {
+	t.Helper()
+	dir := t.TempDir()
+
+	today := time.Now().In(api.Location)
+	fake := fakemonev.New(api.User{
+		ID:                  "participant-1",
+		Name:                "Fake User",
+		InternshipStartDate: today.AddDate(0, -1, 0).Format(api.DateFormat),
+		InternshipEndDate:   today.AddDate(0, 1, 0).Format(api.DateFormat),
+	})
+	t.Cleanup(fake.Close)
+
+	logs, err := json.Marshal(testDailyLogs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logsFile := filepath.Join(dir, "daily_logs.json")
+	if err := os.WriteFile(logsFile, logs, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := runHistory
+	runHistory = history.NewStore(filepath.Join(dir, "history.jsonl"))
+	t.Cleanup(func() { runHistory = previous })
+
+	at := &attendanceTest{
+		cfg: &config.Config{
+			MaganghubConfig:     config.MaganghubConfig{MonevURL: fake.URL()},
+			APITimeout:          5 * time.Second,
+			RunTimeout:          30 * time.Second,
+			DailyLogsFile:       logsFile,
+			LogSources:          []string{"file"},
+			LeaveFile:           filepath.Join(dir, "leave.json"),
+			ValidationRulesFile: filepath.Join(dir, "rules.json"),
+			CookiesFile:         filepath.Join(dir, "cookies.json"),
+		},
+		fake: fake,
+	}
+	at.sess = &session{
+		cookies: cookie_manager.NewCookieManager(at.cfg.CookiesFile),
+		login: func(ctx context.Context, cfg *config.Config) (string, []playwright.Cookie, error) {
+			at.logins++
+			return "Fake User", fake.Cookies(), nil
+		},
+	}
+	return at
+}
+
+func (at *attendanceTest) run(t *testing.T) *history.Entry {
+	t.Helper()
+	if err := runAttendance(context.Background(), at.cfg, at.sess, nil, nil, history.TriggerManual); err != nil {
+		t.Fatalf("runAttendance: %v", err)
+	}
+	last, err := runHistory.Last()
+	if err != nil || last == nil {
+		t.Fatalf("no run recorded: %v", err)
+	}
+	return last
+}
+
+func TestRunAttendanceSubmitsOncePerDay(t *testing.T) {
+	at := newAttendanceTest(t)
+
+	entry := at.run(t)
+	if entry.Outcome != history.OutcomeSubmitted {
+		t.Fatalf("first run outcome = %q, want %q", entry.Outcome, history.OutcomeSubmitted)
+	}
+	subs := at.fake.Submissions()
+	if len(subs) != 1 {
+		t.Fatalf("got %d submissions, want 1", len(subs))
+	}
+	if subs[0].Date != api.Today() || subs[0].Status != string(api.StatusPresent) {
+		t.Errorf("submitted %s %s, want %s %s", subs[0].Date, subs[0].Status, api.Today(), api.StatusPresent)
+	}
+	if entry.AttendanceID == 0 {
+		t.Error("submitted run has no attendance ID")
+	}
+
+	// The second run reuses the saved cookies and sees today is done
+	entry = at.run(t)
+	if entry.Outcome != history.OutcomeAlreadyAttended {
+		t.Errorf("second run outcome = %q, want %q", entry.Outcome, history.OutcomeAlreadyAttended)
+	}
+	if n := len(at.fake.Submissions()); n != 1 {
+		t.Errorf("got %d submissions after the second run, want 1", n)
+	}
+	if at.logins != 1 {
+		t.Errorf("logged in %d times, want 1", at.logins)
+	}
+}
+
+func TestRunAttendanceLogsInAgainWithStaleCookies(t *testing.T) {
+	at := newAttendanceTest(t)
+	stale := []playwright.Cookie{{Name: "accessToken", Value: "expired", Domain: "127.0.0.1", Path: "/"}}
+	if err := at.sess.cookies.Save(stale); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry := at.run(t); entry.Outcome != history.OutcomeSubmitted {
+		t.Fatalf("outcome = %q, want %q", entry.Outcome, history.OutcomeSubmitted)
+	}
+	if at.logins != 1 {
+		t.Errorf("logged in %d times, want 1", at.logins)
+	}
+
+	saved := cookie_manager.NewCookieManager(at.cfg.CookiesFile).Get()
+	if len(saved) != 1 || saved[0].Value != fakemonev.AccessToken {
+		t.Errorf("saved cookies = %+v, want the fresh session", saved)
+	}
+}
+
+func TestRunAttendanceDryRunSubmitsNothing(t *testing.T) {
+	at := newAttendanceTest(t)
+	at.cfg.DryRun = true
+
+	if entry := at.run(t); entry.Outcome != history.OutcomeDryRun {
+		t.Errorf("outcome = %q, want %q", entry.Outcome, history.OutcomeDryRun)
+	}
+	if n := len(at.fake.Submissions()); n != 0 {
+		t.Errorf("got %d submissions, want none", n)
+	}
+}
+
+func TestRunAttendanceFailsOnInvalidRules(t *testing.T) {
+	for name, rules := range map[string]string{
+		"malformed JSON": `{"fields": {"activity_log": {"min_runes": 10`,
+		"unknown field":  `{"fields": {"activity": {"min_runes": 10}}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			at := newAttendanceTest(t)
+			if err := os.WriteFile(at.cfg.ValidationRulesFile, []byte(rules), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := runAttendance(context.Background(), at.cfg, at.sess, nil, nil, history.TriggerManual)
+			if err == nil || !strings.Contains(err.Error(), "validation rules") {
+				t.Errorf("runAttendance error = %v, want a validation rules error", err)
+			}
+			if at.logins != 0 || len(at.fake.Submissions()) != 0 {
+				t.Errorf("logged in %d times and submitted %d, want neither", at.logins, len(at.fake.Submissions()))
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+	"maganghub-autopresence/internal/config"
+	"maganghub-autopresence/internal/history"
+	"maganghub-autopresence/internal/holiday"
+	"maganghub-autopresence/internal/schedule"
+)
+
+// backfillOptions are the flags of the backfill command
+type backfillOptions struct {
+	from string
+	to   string
+	yes  bool
+}
+
+// parseBackfillFlags parses "backfill --from YYYY-MM-DD [--to YYYY-MM-DD] [--yes]"
+func parseBackfillFlags(args []string) (backfillOptions, error) {
+	yesterday := time.Now().In(api.Location).AddDate(0, 0, -1).Format(api.DateFormat)
+
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	from := fs.String("from", "", "first date to fill in, YYYY-MM-DD (required)")
+	to := fs.String("to", yesterday, "last date to fill in, YYYY-MM-DD")
+	yes := fs.Bool("yes", false, "submit every missing date without asking")
+	if err := fs.Parse(args); err != nil {
+		return backfillOptions{}, err
+	}
+
+	if *from == "" {
+		return backfillOptions{}, errors.New("backfill: --from is required")
+	}
+	for _, date := range []string{*from, *to} {
+		if _, err := time.Parse(api.DateFormat, date); err != nil {
+			return backfillOptions{}, fmt.Errorf("backfill: date %q must be YYYY-MM-DD", date)
+		}
+	}
+	if *to < *from {
+		return backfillOptions{}, fmt.Errorf("backfill: --to %s is before --from %s", *to, *from)
+	}
+	if *to > api.Today() {
+		return backfillOptions{}, fmt.Errorf("backfill: --to %s is in the future", *to)
+	}
+
+	return backfillOptions{from: *from, to: *to, yes: *yes}, nil
+}
+
+// runBackfill finds working days between opts.from and opts.to without an
+// attendance record and submits them one by one, asking for confirmation
+// unless opts.yes is set
+func runBackfill(ctx context.Context, cfg *config.Config, sess *session, opts backfillOptions) error {
+	rules, err := loadRules(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Bound the login like a run; later requests still have API_TIMEOUT each
+	loginCtx, cancel := context.WithTimeout(ctx, cfg.RunTimeout)
+	defer cancel()
+	apiClient, user, err := connect(loginCtx, ctx, cfg, sess, nil)
+	if err != nil {
+		return err
+	}
+	if cfg.DryRun {
+		apiClient.DryRun()
+	}
+
+	gaps, err := findGaps(ctx, cfg, apiClient, user, opts)
+	if err != nil {
+		return err
+	}
+	if len(gaps) == 0 {
+		log.Printf("✅ No missing days between %s and %s", opts.from, opts.to)
+		return nil
+	}
+	log.Printf("📋 %d missing day(s): %s", len(gaps), strings.Join(gaps, ", "))
+
+	leaves, err := schedule.LoadLeaveCalendar(cfg.LeaveFile)
+	if err != nil {
+		return err
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+	var submitted, skipped, failed int
+	for _, date := range gaps {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		answer, err := backfillDate(ctx, cfg, apiClient, user, rules, leaves, date, opts.yes, stdin)
+		switch {
+		case err != nil:
+			log.Printf("❌ %s: %v", date, err)
+			failed++
+		case answer == "q":
+			log.Println("Backfill stopped")
+			skipped += len(gaps) - submitted - skipped - failed
+		case answer == "y":
+			submitted++
+		default:
+			skipped++
+		}
+		if answer == "q" {
+			break
+		}
+	}
+
+	log.Printf("Backfill done: %d submitted, %d skipped, %d failed", submitted, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d date(s) failed", failed)
+	}
+	return nil
+}
+
+// findGaps returns the dates in range that the schedule runs on, inside the
+// internship and not a holiday, that have no attendance record yet
+func findGaps(ctx context.Context, cfg *config.Config, apiClient *api.Client, user *api.User, opts backfillOptions) ([]string, error) {
+	from, err := time.ParseInLocation(api.DateFormat, opts.from, api.Location)
+	if err != nil {
+		return nil, fmt.Errorf("backfill: invalid --from: %w", err)
+	}
+	to, err := time.ParseInLocation(api.DateFormat, opts.to, api.Location)
+	if err != nil {
+		return nil, fmt.Errorf("backfill: invalid --to: %w", err)
+	}
+
+	attendances, err := apiClient.GetAttendancesRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(attendances))
+	for _, att := range attendances {
+		existing[att.Date] = true
+	}
+
+	scheduler, err := configuredScheduler(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var holidays *holiday.Calendar
+	if cfg.SkipHolidays {
+		if holidays, err = loadHolidays(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	start, end, periodErr := user.InternshipPeriod()
+
+	var gaps []string
+	warned := make(map[string]bool)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(api.DateFormat)
+		if existing[date] {
+			continue
+		}
+		if periodErr == nil && (date < start || date > end) {
+			continue
+		}
+
+		if runs, err := scheduler.RunsOn(day); err != nil {
+			return nil, err
+		} else if !runs {
+			continue
+		}
+
+		if holidays != nil {
+			if year := date[:4]; !warned[year] {
+				warnUncovered(holidays, date)
+				warned[year] = true
+			}
+			if h, ok := holidays.Lookup(date); ok {
+				log.Printf("🎉 %s is a holiday: %s, not filling it in", date, h)
+				continue
+			}
+		}
+
+		gaps = append(gaps, date)
+	}
+	return gaps, nil
+}
+
+// backfillDate resolves, confirms and submits one missing date. It returns
+// "y" when submitted, "n" when the user skipped it and "q" to stop
+func backfillDate(ctx context.Context, cfg *config.Config, apiClient *api.Client, user *api.User, rules schedule.Rules, leaves *schedule.LeaveCalendar, date string, yes bool, stdin *bufio.Reader) (answer string, err error) {
+	entry := history.Entry{Time: time.Now(), Date: date, Trigger: history.TriggerBackfill}
+
+	// Rebuild the source each time so variants used by earlier dates count
+	logSource, err := newLogSource(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	day, err := time.ParseInLocation(api.DateFormat, date, api.Location)
+	if err != nil {
+		return "", err
+	}
+	leave, _ := leaves.Lookup(date)
+	request, err := buildRequest(ctx, logSource, rules, leave, day, user, &entry)
+	if err != nil {
+		recordRun(entry, err)
+		return "", err
+	}
+
+	printRequest(date, request)
+	answer = "y"
+	if !yes {
+		answer = confirm(stdin, fmt.Sprintf("Submit %s? [y/N/q] ", date))
+		if answer != "y" {
+			return answer, nil
+		}
+	}
+
+	defer func() {
+		recordRun(entry, err)
+	}()
+
+	response, err := apiClient.SubmitAttendanceOn(ctx, date, request)
+	if err != nil {
+		return "", err
+	}
+	if cfg.DryRun {
+		log.Printf("🧪 Dry run, not submitted. Payload: %s", response)
+		entry.Outcome = history.OutcomeDryRun
+		entry.Payload = json.RawMessage(response)
+		return "y", nil
+	}
+	entry.Response = response
+
+	att, err := verifySubmission(ctx, apiClient, date, request.Status)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("✅ Attendance verified: #%d %s (%s, approval %s)", att.ID, att.Date, att.Status, att.ApprovalStatus)
+	entry.Outcome = history.OutcomeSubmitted
+	entry.AttendanceID = att.ID
+	return "y", nil
+}
+
+// confirm asks a yes/no/quit question on stdin; anything but y or q is no
+func confirm(stdin *bufio.Reader, prompt string) string {
+	fmt.Print(prompt)
+	line, _ := stdin.ReadString('\n')
+	switch answer := strings.ToLower(strings.TrimSpace(line)); answer {
+	case "y", "yes":
+		return "y"
+	case "q", "quit":
+		return "q"
+	default:
+		return "n"
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+	"maganghub-autopresence/internal/config"
+	"maganghub-autopresence/internal/fakemonev"
+)
+
+func TestFindGaps(t *testing.T) {
+	// 2026-08-10 is a Monday; the range covers two full weeks
+	opts := backfillOptions{from: "2026-08-10", to: "2026-08-23"}
+
+	tests := []struct {
+		name         string
+		existing     []string
+		start        string
+		end          string
+		skipHolidays bool
+		want         []string
+	}{
+		{
+			name: "every working day is missing",
+			want: []string{"2026-08-10", "2026-08-11", "2026-08-12", "2026-08-13", "2026-08-14", "2026-08-17", "2026-08-18", "2026-08-19", "2026-08-20", "2026-08-21"},
+		},
+		{
+			name:     "existing days are skipped",
+			existing: []string{"2026-08-11", "2026-08-19", "2026-08-21"},
+			want:     []string{"2026-08-10", "2026-08-12", "2026-08-13", "2026-08-14", "2026-08-17", "2026-08-18", "2026-08-20"},
+		},
+		{
+			name:  "days outside the internship are skipped",
+			start: "2026-08-12",
+			end:   "2026-08-18",
+			want:  []string{"2026-08-12", "2026-08-13", "2026-08-14", "2026-08-17", "2026-08-18"},
+		},
+		{
+			name:         "holidays are skipped",
+			skipHolidays: true,
+			want:         []string{"2026-08-10", "2026-08-11", "2026-08-12", "2026-08-13", "2026-08-14", "2026-08-18", "2026-08-19", "2026-08-20", "2026-08-21"},
+		},
+		{
+			name:         "all at once",
+			existing:     []string{"2026-08-13", "2026-08-20"},
+			start:        "2026-08-11",
+			end:          "2026-08-21",
+			skipHolidays: true,
+			want:         []string{"2026-08-11", "2026-08-12", "2026-08-14", "2026-08-18", "2026-08-19", "2026-08-21"},
+		},
+	}
+
+	dir := t.TempDir()
+	holidays := filepath.Join(dir, "holidays.json")
+	if err := os.WriteFile(holidays, []byte(`{"holidays": [{"date": "2026-08-17", "name": "Hari Kemerdekaan"}, {"date": "2026-08-22", "name": "Weekend holiday"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := api.User{ID: "participant-1", InternshipStartDate: tt.start, InternshipEndDate: tt.end}
+			fake := fakemonev.New(user)
+			t.Cleanup(fake.Close)
+			for _, date := range tt.existing {
+				fake.AddAttendance(api.Attendance{Date: date, Status: "PRESENT"})
+			}
+
+			client := api.NewClient(fake.URL(), 5*time.Second, fake.Cookies())
+			me, err := client.GetMe(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg := &config.Config{CronSchedule: "0 8 * * 1-5", SkipHolidays: tt.skipHolidays, HolidayFile: holidays}
+
+			got, err := findGaps(context.Background(), cfg, client, me, opts)
+			if err != nil {
+				t.Fatalf("findGaps: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findGaps = %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+	"maganghub-autopresence/internal/config"
+	"maganghub-autopresence/internal/history"
+	"maganghub-autopresence/internal/schedule"
+)
+
+// runOnce does a single attendance run without a scheduler, so failures are
+// reported through the exit code instead of being retried later
+func runOnce(ctx context.Context, cfg *config.Config, sess *session) error {
+	err := runAttendance(ctx, cfg, sess, nil, nil, history.TriggerManual)
+	if errors.Is(err, errInternshipEnded) {
+		log.Printf("👋 %v, nothing to submit", err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Tell a daemon sharing the state file that today is done
+	if !cfg.DryRun {
+		if err := schedule.NewRunState(cfg.RunStateFile).MarkSuccess(api.Today()); err != nil {
+			log.Printf("Warning: Failed to save run state: %v", err)
+		}
+	}
+	return nil
+}
+
+// printStatus prints the profile, today's attendance, the last recorded run
+// and the next scheduled run
+func printStatus(parent context.Context, cfg *config.Config, sess *session) error {
+	ctx, cancel := context.WithTimeout(parent, cfg.RunTimeout)
+	defer cancel()
+
+	apiClient, user, err := connect(ctx, parent, cfg, sess, nil)
+	if err != nil {
+		return err
+	}
+
+	today := api.Today()
+	att, err := apiClient.FindAttendance(ctx, today)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("Name:        %s (%s)\n", user.Name, user.ID)
+	fmt.Printf("Role:        %s at %s\n", user.JobRole, user.InternshipCompany)
+	if user.MentorName != "" {
+		fmt.Printf("Mentor:      %s\n", user.MentorName)
+	}
+	if start, end, err := user.InternshipPeriod(); err == nil {
+		fmt.Printf("Internship:  %s to %s\n", start, end)
+	}
+
+	switch {
+	case att != nil:
+		fmt.Printf("Today:       %s, #%d %s (approval %s)\n", today, att.ID, att.Status, att.ApprovalStatus)
+	default:
+		fmt.Printf("Today:       %s, not submitted yet%s\n", today, dayNote(cfg, today))
+	}
+
+	if last, err := runHistory.Last(); err != nil {
+		log.Printf("Warning: Failed to read run history: %v", err)
+	} else if last != nil {
+		line := fmt.Sprintf("%s %s (%s)", last.Time.Local().Format("2006-01-02 15:04:05"), last.Outcome, last.Trigger)
+		if last.Reason != "" {
+			line += ": " + last.Reason
+		}
+		if last.Error != "" {
+			line += ": " + last.Error
+		}
+		fmt.Printf("Last run:    %s\n", line)
+	}
+
+	next, err := nextScheduledRun(cfg)
+	if err != nil {
+		fmt.Printf("Next run:    invalid schedule: %v\n", err)
+	} else {
+		fmt.Printf("Next run:    %s\n", next.Format("2006-01-02 15:04:05 MST"))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// dayNote explains why no attendance is expected today, if that is the case
+func dayNote(cfg *config.Config, today string) string {
+	var notes []string
+	if cfg.SkipHolidays {
+		if holidays, err := loadHolidays(cfg); err == nil {
+			if h, ok := holidays.Lookup(today); ok {
+				notes = append(notes, "holiday: "+h.String())
+			}
+		}
+	}
+	if leaves, err := schedule.LoadLeaveCalendar(cfg.LeaveFile); err == nil {
+		if leave, ok := leaves.Lookup(today); ok {
+			notes = append(notes, fmt.Sprintf("planned %s", leave.Status))
+		}
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(notes, ", ") + ")"
+}
+
+// nextScheduledRun returns when the daemon would run next with cfg
+func nextScheduledRun(cfg *config.Config) (time.Time, error) {
+	scheduler, err := configuredScheduler(cfg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return scheduler.NextRunAfter(time.Now())
+}
+
+// configuredScheduler builds the scheduler for cfg's cron expression and
+// optional run window without starting it
+func configuredScheduler(cfg *config.Config) (*schedule.Scheduler, error) {
+	var window *schedule.Window
+	if cfg.RunWindow != "" {
+		var err error
+		window, err = schedule.ParseWindow(cfg.RunWindow, cfg.RunWindowSeed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RUN_WINDOW: %w", err)
+		}
+	}
+	return schedule.NewScheduler(cfg.CronSchedule, window), nil
+}
+
+// printRequest shows the daily log for date, as resolved for a dry run or
+// before a backfill asks for confirmation
+func printRequest(date string, request api.DailyLogRequest) {
+	fmt.Println()
+	fmt.Printf("Date:           %s\n", date)
+	fmt.Printf("Status:         %s\n", request.Status)
+	fmt.Printf("Activity log:   %s\n", request.ActivityLog)
+	if request.LessonLearned != "" {
+		fmt.Printf("Lesson learned: %s\n", request.LessonLearned)
+	}
+	if request.Obstacles != "" {
+		fmt.Printf("Obstacles:      %s\n", request.Obstacles)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"maganghub-autopresence/internal/config"
+	"maganghub-autopresence/internal/schedule"
+)
+
+// logsUsage is printed for "logs" without a valid subcommand
+const logsUsage = `usage:
+  maganghub-autopresence logs lint [-rules validation_rules.json] [daily_logs.json|.yaml|.md]
+  maganghub-autopresence logs fmt [-w] [daily_logs.json]`
+
+// runLogsCommand implements the "logs" subcommand and returns the exit code
+func runLogsCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, logsUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "lint":
+		return lintLogs(args[1:])
+	case "fmt":
+		return formatLogs(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown logs command %q\n%s\n", args[0], logsUsage)
+		return 2
+	}
+}
+
+// lintLogs validates a daily log file and exits non-zero when any
+// finding is an error
+func lintLogs(args []string) int {
+	fs := flag.NewFlagSet("logs lint", flag.ContinueOnError)
+	rulesFile := fs.String("rules", envOr("VALIDATION_RULES_FILE", "validation_rules.json"), "validation rules file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	path := logsFileArg(fs)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	rules, err := schedule.LoadRules(*rulesFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	findings := schedule.LintDailyLogs(path, data, rules)
+	errorCount := 0
+	for _, f := range findings {
+		if f.Severity == schedule.SeverityError {
+			errorCount++
+		}
+		// Print compiler-style "file:line:col: severity: message" positions
+		position := path
+		if f.Line > 0 {
+			position = fmt.Sprintf("%s:%d:%d", path, f.Line, f.Column)
+			f.Line, f.Column = 0, 0
+		}
+		fmt.Printf("%s: %s: %s\n", position, f.Severity, f)
+	}
+
+	if errorCount > 0 {
+		fmt.Printf("❌ %s: %d error(s), %d warning(s)\n", path, errorCount, len(findings)-errorCount)
+		return 1
+	}
+	fmt.Printf("✅ %s: 0 errors, %d warning(s)\n", path, len(findings))
+	return 0
+}
+
+// formatLogs prints a normalised daily log file, or rewrites it with -w
+func formatLogs(args []string) int {
+	fs := flag.NewFlagSet("logs fmt", flag.ContinueOnError)
+	write := fs.Bool("w", false, "write the result back to the file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	path := logsFileArg(fs)
+	if format := schedule.DailyLogFormat(path); format != "json" {
+		fmt.Fprintf(os.Stderr, "❌ logs fmt only supports JSON, %s is %s\n", path, format)
+		return 1
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	formatted, err := schedule.FormatDailyLogs(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %s:%v\n", path, err)
+		return 1
+	}
+
+	if !*write {
+		os.Stdout.Write(formatted)
+		return 0
+	}
+	if bytes.Equal(data, formatted) {
+		return 0
+	}
+	if err := os.WriteFile(path, formatted, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	fmt.Printf("✏️  %s formatted\n", path)
+	return 0
+}
+
+// logsFileArg returns the file argument, defaulting to DAILY_LOGS_FILE
+func logsFileArg(fs *flag.FlagSet) string {
+	if fs.NArg() > 0 {
+		return fs.Arg(0)
+	}
+	return envOr("DAILY_LOGS_FILE", config.DetectDailyLogsFile())
+}
+
+// envOr reads an environment variable, falling back to def when unset.
+// The logs commands don't need credentials so they skip config.Load
+func envOr(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -11,54 +16,156 @@ import (
 	"maganghub-autopresence/internal/browser"
 	"maganghub-autopresence/internal/config"
 	"maganghub-autopresence/internal/cookie_manager"
+	"maganghub-autopresence/internal/history"
+	"maganghub-autopresence/internal/holiday"
+	"maganghub-autopresence/internal/metrics"
 	"maganghub-autopresence/internal/schedule"
 
 	"github.com/playwright-community/playwright-go"
 )
 
+// usage lists the subcommands
+const usage = `usage: maganghub-autopresence [command]
+
+commands:
+  daemon    verify login, then submit on the cron schedule (default)
+  run       do one attendance run now and exit, for system cron or CI
+  dry-run   resolve and print today's daily log without submitting it
+  status    print the profile, today's attendance and the next scheduled run
+  backfill  fill in missed past days: backfill --from YYYY-MM-DD [--to YYYY-MM-DD] [--yes]
+  logs      lint or format the daily log file, see "logs" for usage`
+
 func main() {
+	command := "daemon"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	switch command {
+	case "logs":
+		os.Exit(runLogsCommand(os.Args[2:]))
+	case "daemon", "run", "dry-run", "status", "backfill":
+		os.Exit(runCommand(command, os.Args[2:]))
+	case "help", "-h", "-help", "--help":
+		fmt.Println(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s\n", command, usage)
+		os.Exit(2)
+	}
+}
+
+// runCommand runs one of the subcommands that need the config and
+// returns the exit code
+func runCommand(command string, args []string) int {
+	var backfill backfillOptions
+	if command == "backfill" {
+		var err error
+		if backfill, err = parseBackfillFlags(args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+
 	cfg := config.Load()
 
+	// Cancel in-flight work on interrupt so shutdown does not wait on a hung request
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// The daemon may also end itself, e.g. once the internship is over
+	ctx, shutdown := context.WithCancelCause(ctx)
+	defer shutdown(nil)
+
+	runHistory = history.NewStore(cfg.HistoryFile)
+	sess := newSession(cfg)
+
+	var err error
+	switch command {
+	case "run":
+		err = runOnce(ctx, cfg, sess)
+	case "dry-run":
+		cfg.DryRun = true
+		err = runOnce(ctx, cfg, sess)
+	case "status":
+		err = printStatus(ctx, cfg, sess)
+	case "backfill":
+		err = runBackfill(ctx, cfg, sess, backfill)
+	default:
+		runDaemon(ctx, cfg, sess, shutdown)
+	}
+
+	if err != nil {
+		log.Printf("❌ %s failed: %v", command, err)
+		return 1
+	}
+	return 0
+}
+
+// runDaemon verifies the login and then submits attendance on the cron
+// schedule until ctx is cancelled. It calls shutdown itself once the
+// internship has ended
+func runDaemon(ctx context.Context, cfg *config.Config, sess *session, shutdown context.CancelCauseFunc) {
 	// Load and validate daily logs at startup
-	logs, err := schedule.LoadDailyLogs("daily_logs.json")
+	logs, err := schedule.LoadDailyLogs(cfg.DailyLogsFile)
 	if err != nil {
-		log.Printf("⚠️  Warning: Failed to load daily_logs.json: %v", err)
+		log.Printf("⚠️  Warning: Failed to load %s: %v", cfg.DailyLogsFile, err)
+	} else if rules, err := loadRules(cfg); err != nil {
+		log.Printf("❌ %v; every run fails until it is fixed", err)
 	} else {
-		warnings := schedule.ValidateDailyLogs(logs)
-		if len(warnings) > 0 {
-			log.Println("⚠️  WARNING: Beberapa field daily log terlalu pendek!")
-			for _, w := range warnings {
-				log.Printf("   - %s", w)
+		findings := schedule.ValidateDailyLogs(logs, rules)
+		if len(findings) > 0 {
+			log.Println("⚠️  WARNING: Beberapa field daily log tidak lolos validasi!")
+			for _, f := range findings {
+				log.Printf("   - %-7s %s", f.Severity, f)
+			}
+			if schedule.HasErrors(findings) {
+				log.Println("❌ A daily log with errors is refused when it is picked for submission")
 			}
 		} else {
 			log.Printf("✅ Daily logs validated: %d entries OK", len(logs))
 		}
 	}
 
+	if cfg.SkipHolidays {
+		holidays, err := loadHolidays(cfg)
+		if err != nil {
+			log.Printf("⚠️  Warning: Failed to load holiday calendar: %v", err)
+		} else {
+			log.Printf("📅 Holiday calendar loaded: %d days off", holidays.Len())
+			warnUncovered(holidays, api.Today())
+		}
+	}
+
 	// Verify login at startup
 	log.Println("🔐 Verifying login credentials...")
-	client, err := browser.NewBrowserClient(cfg.Headless)
-	if err != nil {
-		log.Fatalf("❌ Failed to create browser: %v", err)
-	}
-	userName, err := client.Login(cfg.MaganghubConfig.Username, cfg.MaganghubConfig.Password)
+	userName, _, err := sess.login(ctx, cfg)
 	if err != nil {
-		client.Close()
 		log.Fatalf("❌ Login failed: %v", err)
 	}
 	log.Printf("✅ Login verified as: %s", userName)
-	client.Close()
-
-	// Run attendance check on startup
-	log.Println("📋 Checking attendance for today...")
-	runAttendance(cfg, nil)
 
-	// Create scheduler with cron from config
-	scheduler := schedule.NewScheduler(cfg.CronSchedule)
+	// Create scheduler with cron from config, optionally drawing the time from a window
+	scheduler, err := configuredScheduler(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	scheduler.EnableCatchUp(schedule.NewRunState(cfg.RunStateFile), cfg.CatchUpGrace)
 
 	// Define the attendance job
-	attendanceJob := func() {
-		runAttendance(cfg, scheduler)
+	watch := &internshipWatch{}
+	attendanceJob := func(trigger history.Trigger) error {
+		err := runAttendance(ctx, cfg, sess, scheduler, watch, trigger)
+		if errors.Is(err, errInternshipEnded) {
+			shutdown(err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if cfg.DryRun {
+			return schedule.ErrDryRun
+		}
+		return nil
 	}
 
 	// Start the scheduler
@@ -66,160 +173,630 @@ func main() {
 		log.Fatalf("Failed to start scheduler: %v", err)
 	}
 
-	log.Printf("Next scheduled run: %s", scheduler.GetNextRun())
+	// Serve the admin API and dashboard when ADMIN_ADDR is set, before a
+	// catch-up run so that run can be watched
+	startAdminServer(ctx, cfg, sess, scheduler)
+
+	// Run today's slot now if it was missed while the daemon was down
+	log.Println("📋 Checking for a missed run today...")
+	scheduler.CatchUp(time.Now(), history.TriggerStartup)
 
 	// Wait for interrupt signal to gracefully shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	<-ctx.Done()
 
+	if cause := context.Cause(ctx); errors.Is(cause, errInternshipEnded) {
+		log.Printf("👋 %v, stopping daemon", cause)
+	}
 	log.Println("Shutting down scheduler...")
 	scheduler.Stop()
 }
 
-// Global cookie manager
-var cookieManager = cookie_manager.NewCookieManager("cookies.json")
+// browserLogin logs in through a fresh browser and returns the user name and
+// session cookies. Cancelling ctx closes the browser and aborts the login
+func browserLogin(ctx context.Context, cfg *config.Config) (string, []playwright.Cookie, error) {
+	client, err := browser.NewBrowserClient(cfg.Headless, cfg.MaganghubConfig.AuthURL, cfg.MaganghubConfig.MonevURL)
+	if err != nil {
+		return "", nil, fmt.Errorf("browser error: %w", err)
+	}
+	defer client.Close()
+
+	stopAbort := context.AfterFunc(ctx, func() { client.Close() })
+	defer stopAbort()
+
+	userName, err := client.Login(cfg.MaganghubConfig.Username, cfg.MaganghubConfig.Password)
+	if ctx.Err() != nil {
+		return "", nil, ctx.Err()
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("login error: %w", err)
+	}
 
-func runAttendance(cfg *config.Config, scheduler *schedule.Scheduler) {
-	// Load daily logs from JSON
-	logs, err := schedule.LoadDailyLogs("daily_logs.json")
+	cookies, err := client.GetCookies()
 	if err != nil {
-		log.Printf("Failed to load daily logs: %v", err)
-		return
+		return "", nil, fmt.Errorf("cookie error: %w", err)
 	}
 
-	// Get today's log entry
-	todayLog := schedule.GetTodayLog(logs)
-	if todayLog == nil {
-		log.Println("No log entry found for today")
+	return userName, cookies, nil
+}
+
+// sleepContext waits for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// loadHolidays returns the user-supplied holiday calendar, or the bundled one
+func loadHolidays(cfg *config.Config) (*holiday.Calendar, error) {
+	if cfg.HolidayFile != "" {
+		return holiday.Load(cfg.HolidayFile)
+	}
+	return holiday.Bundled()
+}
+
+// warnUncovered warns when the holiday calendar has no entries for the year
+// of date, so holidays would silently not be skipped
+func warnUncovered(holidays *holiday.Calendar, date string) {
+	if !holidays.Covers(date) {
+		log.Printf("⚠️  Warning: The holiday calendar has no holidays in %s, holidays are not skipped; set HOLIDAY_FILE to a calendar for that year", date[:4])
+	}
+}
+
+// loadRules returns the validation rules from cfg. Only a missing file
+// falls back to the defaults; an unreadable or invalid one is an error, so
+// a typo does not quietly loosen the checks
+func loadRules(cfg *config.Config) (schedule.Rules, error) {
+	rules, err := schedule.LoadRules(cfg.ValidationRulesFile)
+	if err != nil {
+		return schedule.Rules{}, fmt.Errorf("validation rules: %w", err)
+	}
+	return rules, nil
+}
+
+// session logs in to Monev and caches the session cookies between runs
+type session struct {
+	cookies *cookie_manager.CookieManager
+	// login signs in and returns the user name and cookies
+	login func(ctx context.Context, cfg *config.Config) (string, []playwright.Cookie, error)
+}
+
+// newSession returns a session that caches cookies in cfg.CookiesFile and
+// logs in through the browser
+func newSession(cfg *config.Config) *session {
+	return &session{
+		cookies: cookie_manager.NewCookieManager(cfg.CookiesFile),
+		login:   browserLogin,
+	}
+}
+
+// runHistory records every attendance run; main opens it from config
+var runHistory *history.Store
+
+// errInternshipEnded is returned by runAttendance once the internship end
+// date has passed; the daemon stops with it as the cause
+var errInternshipEnded = errors.New("internship has ended")
+
+// internshipWatch remembers the internship end date from the last profile
+// fetched, so the daemon also stops after the end when it can no longer
+// log in, e.g. because the account was closed
+type internshipWatch struct {
+	mu  sync.Mutex
+	end string
+}
+
+// remember records the end date from a freshly fetched profile
+func (w *internshipWatch) remember(end string) {
+	if w == nil {
 		return
 	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.end = end
+}
 
-	log.Printf("Using log for: %s", todayLog.Day)
+// ended returns errInternshipEnded when today is past the remembered end date
+func (w *internshipWatch) ended(today string) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.end != "" && today > w.end {
+		return fmt.Errorf("%w on %s", errInternshipEnded, w.end)
+	}
+	return nil
+}
+
+// runAttendance submits today's attendance unless there is nothing to do.
+// It returns nil once today needs no further work, whether that is because
+// attendance was submitted and verified or because today is skipped
+//
+// watch may be nil; with it a run after the last known internship end
+// date returns errInternshipEnded without logging in
+func runAttendance(parent context.Context, cfg *config.Config, sess *session, scheduler *schedule.Scheduler, watch *internshipWatch, trigger history.Trigger) (err error) {
+	if parent.Err() != nil {
+		log.Println("Skipping attendance run, shutting down")
+		return parent.Err()
+	}
+
+	// Bound the whole run; retries are scheduled against parent, not this deadline
+	ctx, cancel := context.WithTimeout(parent, cfg.RunTimeout)
+	defer cancel()
+
+	today := api.Today()
+
+	entry := history.Entry{Time: time.Now(), Date: today, Trigger: trigger}
+	defer func() {
+		recordRun(entry, err)
+	}()
+
+	// No attendance is expected on public holidays and cuti bersama
+	if cfg.SkipHolidays {
+		holidays, err := loadHolidays(cfg)
+		if err != nil {
+			log.Printf("Failed to load holiday calendar: %v", err)
+			return err
+		}
+		warnUncovered(holidays, today)
+		if h, ok := holidays.Lookup(today); ok {
+			log.Printf("🎉 %s is a holiday: %s, skipping submission", today, h)
+			entry.Outcome = history.OutcomeSkipped
+			entry.Reason = "holiday: " + h.String()
+			return nil
+		}
+	}
+
+	// Planned absences take precedence over the daily log
+	leaves, err := schedule.LoadLeaveCalendar(cfg.LeaveFile)
+	if err != nil {
+		log.Printf("Failed to load leave calendar: %v", err)
+		return err
+	}
+
+	leave, onLeave := leaves.Lookup(today)
+
+	// Check the log sources are configured before spending a browser login on them
+	var logSource schedule.LogSource
+	if !onLeave {
+		logSource, err = newLogSource(cfg)
+		if err != nil {
+			log.Printf("Invalid log source configuration: %v", err)
+			return err
+		}
+	}
+
+	rules, err := loadRules(cfg)
+	if err != nil {
+		log.Printf("Failed to load validation rules: %v", err)
+		return err
+	}
+
+	if err := watch.ended(today); err != nil {
+		log.Printf("🏁 %v, not logging in", err)
+		return err
+	}
+
+	apiClient, user, err := connect(ctx, parent, cfg, sess, scheduler)
+	if err != nil {
+		return err
+	}
+	if cfg.DryRun {
+		// Everything up to the POST still runs against the real API
+		apiClient.DryRun()
+	}
+	log.Printf("User: %s (%s)", user.Name, user.ID)
+
+	if ok, err := checkInternshipPeriod(cfg, user, watch, today); !ok {
+		entry.Outcome = history.OutcomeSkipped
+		entry.Reason = "outside internship period"
+		return err
+	}
+
+	// Check if already attended today
+	hasAttended, err := apiClient.HasAttendedToday(ctx)
+	if err != nil {
+		log.Printf("HasAttendedToday error: %v", err)
+		handleAPIError(parent, cfg, sess, scheduler, err)
+		return err
+	}
+
+	switch {
+	case hasAttended && cfg.DryRun:
+		log.Println("Already attended today, resolving the daily log anyway (dry run)")
+	case hasAttended:
+		log.Println("Already attended today, skipping submission")
+		entry.Outcome = history.OutcomeAlreadyAttended
+		return nil
+	}
+
+	request, err := buildRequest(ctx, logSource, rules, leave, time.Now().In(api.Location), user, &entry)
+	if err != nil {
+		return err
+	}
+
+	// Submit attendance with today's log
+	response, err := apiClient.SubmitAttendance(ctx, request)
+	if err != nil {
+		log.Printf("SubmitAttendance error: %v", err)
+		handleAPIError(parent, cfg, sess, scheduler, err)
+		return err
+	}
+
+	if cfg.DryRun {
+		printRequest(today, request)
+		log.Printf("🧪 Dry run, not submitted. Payload: %s", response)
+		entry.Outcome = history.OutcomeDryRun
+		entry.Payload = json.RawMessage(response)
+		return nil
+	}
+
+	log.Printf("Attendance submitted: %s", response)
+	entry.Response = response
+
+	// The response body alone does not prove the record exists, so read it back
+	att, err := verifySubmission(ctx, apiClient, today, request.Status)
+	if err != nil {
+		log.Printf("❌ Submission could not be verified: %v", err)
+		handleAPIError(parent, cfg, sess, scheduler, err)
+		return err
+	}
+
+	log.Printf("✅ Attendance verified: #%d %s (%s, approval %s)", att.ID, att.Date, att.Status, att.ApprovalStatus)
+	entry.Outcome = history.OutcomeSubmitted
+	entry.AttendanceID = att.ID
+	return nil
+}
+
+// buildRequest returns the attendance request for date: the planned
+// absence when leave is set, otherwise the daily log from logSource,
+// refused when it breaks an error-severity rule. It fills the log fields
+// of the history entry
+func buildRequest(ctx context.Context, logSource schedule.LogSource, rules schedule.Rules, leave *schedule.LeaveEntry, date time.Time, user *api.User, entry *history.Entry) (api.DailyLogRequest, error) {
+	day := date.Format(api.DateFormat)
+
+	if leave != nil {
+		log.Printf("🏖️  %s is marked as %s: %s", day, leave.Status, leave.Reason)
+		entry.Status = string(leave.Status)
+		entry.Log = &history.LogText{Day: "Leave", ActivityLog: leave.Reason}
+		return api.DailyLogRequest{
+			Status:      leave.Status,
+			ActivityLog: leave.Reason,
+		}, nil
+	}
+
+	resolved, err := logSource.Resolve(ctx, date, user)
+	if err != nil {
+		log.Printf("Failed to resolve daily log: %v", err)
+		return api.DailyLogRequest{}, err
+	}
+	if resolved == nil {
+		log.Printf("No log entry found for %s", day)
+		return api.DailyLogRequest{}, fmt.Errorf("no daily log entry for %s", day)
+	}
+
+	log.Printf("Using log for: %s", resolved.Day)
+	findings := schedule.ValidateResolved(resolved, rules)
+	for _, f := range findings {
+		log.Printf("   - %-7s %s", f.Severity, f)
+	}
+	if schedule.HasErrors(findings) {
+		return api.DailyLogRequest{}, fmt.Errorf("daily log for %s fails validation, not submitting", day)
+	}
+
+	entry.Status = string(api.StatusPresent)
+	entry.Log = &history.LogText{
+		Day:           resolved.Day,
+		ActivityLog:   resolved.ActivityLog,
+		LessonLearned: resolved.LessonLearned,
+		Obstacles:     resolved.Obstacles,
+	}
+	entry.Variants = resolved.Variants
+	return api.DailyLogRequest{
+		Status:        api.StatusPresent,
+		ActivityLog:   resolved.ActivityLog,
+		LessonLearned: resolved.LessonLearned,
+		Obstacles:     resolved.Obstacles,
+	}, nil
+}
+
+// newLogSource builds the daily log sources named in cfg.LogSources as a
+// chain, wrapped to write the activity log from commits when cfg.GitRepos
+// is set. File-based sources avoid variants submitted recently
+func newLogSource(cfg *config.Config) (schedule.LogSource, error) {
+	used := recentUsage()
+
+	var chain schedule.ChainSource
+	for _, name := range cfg.LogSources {
+		switch name {
+		case "file":
+			chain = append(chain, &schedule.FileSource{Path: cfg.DailyLogsFile, Used: used, AvoidDays: cfg.LogRepeatWindowDays})
+		case "dir":
+			chain = append(chain, &schedule.DirSource{Dir: cfg.DailyLogsDir, Used: used, AvoidDays: cfg.LogRepeatWindowDays})
+		case "command":
+			if cfg.LogCommand == "" {
+				return nil, errors.New("LOG_SOURCES includes command but LOG_COMMAND is not set")
+			}
+			chain = append(chain, &schedule.CommandSource{Command: cfg.LogCommand, Timeout: cfg.LogCommandTimeout})
+		default:
+			return nil, fmt.Errorf("unknown log source %q in LOG_SOURCES, use file, dir or command", name)
+		}
+	}
+
+	var source schedule.LogSource = chain
+	if len(chain) == 1 {
+		source = chain[0]
+	}
+	if len(cfg.GitRepos) == 0 {
+		return source, nil
+	}
+
+	tmpl, err := schedule.LoadCommitTemplate(cfg.GitSummaryTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("commit template: %w", err)
+	}
+	return &schedule.GitSource{Base: source, Repos: cfg.GitRepos, Author: cfg.GitLogAuthor, Template: tmpl}, nil
+}
+
+// connect returns an API client and the user's profile, reusing cached
+// cookies while they are valid and logging in through the browser
+// otherwise. Retries after a failure are scheduled against parent
+func connect(ctx, parent context.Context, cfg *config.Config, sess *session, scheduler *schedule.Scheduler) (*api.Client, *api.User, error) {
+	var err error
 
 	// Try to use cached cookies first
 	var apiClient *api.Client
 	var cachedUser *api.User
-	if cookieManager.HasCookies() {
+	if sess.cookies.HasCookies() {
 		log.Println("🔄 Trying with cached cookies...")
-		apiClient = api.NewClient(cookieManager.Get())
+		apiClient = api.NewClient(cfg.MaganghubConfig.MonevURL, cfg.APITimeout, sess.cookies.Get())
 
 		// Test if cookies are still valid by calling GetMe
-		cachedUser, err = apiClient.GetMe()
-		if err != nil || cachedUser.ID == "" {
+		cachedUser, err = apiClient.GetMe(ctx)
+		switch {
+		case err == nil:
+			log.Println("✅ Cached cookies still valid")
+			metrics.CookieCacheHits.Inc()
+		case errors.Is(err, api.ErrUnauthorized):
 			log.Println("⚠️  Cached cookies expired, re-logging in...")
 			apiClient = nil
 			cachedUser = nil
-		} else {
-			log.Println("✅ Cached cookies still valid")
+		case isServiceUnavailable(err):
+			log.Printf("❌ Monev is unavailable: %v", err)
+			scheduleRetry(parent, scheduler, serviceRetryDelay)
+			return nil, nil, err
+		default:
+			log.Printf("⚠️  Could not verify cached cookies (%v), re-logging in...", err)
+			apiClient = nil
+			cachedUser = nil
 		}
 	}
 
 	// If no valid cookies, login fresh with retry logic
 	if apiClient == nil {
+		metrics.CookieCacheMisses.Inc()
 		const maxRetries = 3
 		var cookies []playwright.Cookie
 		var loginErr error
 
 		for attempt := 1; attempt <= maxRetries; attempt++ {
 			log.Printf("🔐 Logging in... (attempt %d/%d)", attempt, maxRetries)
-			client, err := browser.NewBrowserClient(cfg.Headless)
-			if err != nil {
-				log.Printf("Browser error: %v", err)
-				loginErr = err
-				if attempt < maxRetries {
-					log.Printf("⏳ Waiting 5 seconds before retry...")
-					time.Sleep(5 * time.Second)
-				}
-				continue
+			var userName string
+			userName, cookies, loginErr = sess.login(ctx, cfg)
+			if loginErr == nil {
+				log.Printf("✅ Logged in as: %s", userName)
+				break
 			}
-
-			userName, err := client.Login(cfg.MaganghubConfig.Username, cfg.MaganghubConfig.Password)
-			if err != nil {
-				client.Close()
-				log.Printf("Login error: %v", err)
-				loginErr = err
-				if attempt < maxRetries {
-					log.Printf("⏳ Waiting 5 seconds before retry...")
-					time.Sleep(5 * time.Second)
-				}
-				continue
+			if ctx.Err() != nil {
+				log.Printf("Login aborted: %v", ctx.Err())
+				return nil, nil, ctx.Err()
 			}
-			log.Printf("✅ Logged in as: %s", userName)
-
-			cookies, err = client.GetCookies()
-			client.Close()
-			if err != nil {
-				log.Printf("Cookie error: %v", err)
-				loginErr = err
-				if attempt < maxRetries {
-					log.Printf("⏳ Waiting 5 seconds before retry...")
-					time.Sleep(5 * time.Second)
+
+			log.Printf("%v", loginErr)
+			if attempt < maxRetries {
+				log.Printf("⏳ Waiting 5 seconds before retry...")
+				if err := sleepContext(ctx, 5*time.Second); err != nil {
+					log.Printf("Login aborted: %v", err)
+					return nil, nil, err
 				}
-				continue
 			}
-
-			// Success - break out of retry loop
-			loginErr = nil
-			break
 		}
 
 		if loginErr != nil {
-			log.Printf("❌ Login failed after %d attempts: %v", maxRetries, loginErr)
-			if scheduler != nil {
-				// Schedule retry in 3 hours when scheduler is available.
-				scheduler.ScheduleOnce(3*time.Hour, func() {
-					runAttendance(cfg, scheduler)
-				})
-			}
-			return
+			err := fmt.Errorf("%w after %d attempts: %w", errLoginFailed, maxRetries, loginErr)
+			log.Printf("❌ %v", err)
+			scheduleRetry(parent, scheduler, retryDelay(err))
+			return nil, nil, err
 		}
 
 		// Save cookies for future use
-		if err := cookieManager.Save(cookies); err != nil {
+		if err := sess.cookies.Save(cookies); err != nil {
 			log.Printf("Warning: Failed to save cookies: %v", err)
 		}
 
-		apiClient = api.NewClient(cookies)
+		apiClient = api.NewClient(cfg.MaganghubConfig.MonevURL, cfg.APITimeout, cookies)
 	}
 
 	// Get user profile (reuse cached user if available)
-	var user *api.User
-	if cachedUser != nil {
-		user = cachedUser
-	} else {
-		user, err = apiClient.GetMe()
+	user := cachedUser
+	if user == nil {
+		user, err = apiClient.GetMe(ctx)
 		if err != nil {
 			log.Printf("GetMe error: %v", err)
-			cookieManager.Clear() // Clear invalid cookies
-			return
+			if isServiceUnavailable(err) {
+				scheduleRetry(parent, scheduler, serviceRetryDelay)
+			} else {
+				sess.cookies.Clear() // Clear invalid cookies
+			}
+			return nil, nil, err
 		}
 	}
-	log.Printf("User: %s (%s)", user.Name, user.ID)
+	return apiClient, user, nil
+}
 
-	// Check if already attended today
-	hasAttended, err := apiClient.HasAttendedToday()
+// recentUsage returns when each daily log variant was last submitted
+func recentUsage() schedule.UsageHistory {
+	if runHistory == nil {
+		return nil
+	}
+	entries, err := runHistory.Query(history.Query{Outcomes: []history.Outcome{history.OutcomeSubmitted}})
 	if err != nil {
-		log.Printf("HasAttendedToday error: %v", err)
-		return
+		log.Printf("Warning: Failed to read run history, variants may repeat: %v", err)
+		return nil
 	}
+	return schedule.UsageFromHistory(entries)
+}
 
-	if hasAttended {
-		log.Println("Already attended today, skipping submission")
+// recordRun counts the run in the metrics and appends it to the history
+// store; a run that returned an error is recorded as failed
+func recordRun(entry history.Entry, err error) {
+	switch {
+	case errors.Is(err, errInternshipEnded):
+		// Not a failure, there is simply nothing left to submit
+		entry.Outcome = history.OutcomeSkipped
+		entry.Reason = err.Error()
+	case err != nil:
+		entry.Outcome = history.OutcomeFailed
+		entry.Error = err.Error()
+	}
+	metrics.Runs.Inc(string(entry.Outcome))
+	if entry.Outcome == history.OutcomeSubmitted {
+		metrics.LastSuccess.SetTime(entry.Time)
+	}
+
+	if runHistory == nil {
 		return
 	}
+	if err := runHistory.Append(entry); err != nil {
+		log.Printf("Warning: Failed to write run history: %v", err)
+	}
+}
 
-	// Submit attendance with today's log
-	response, err := apiClient.SubmitAttendance(api.DailyLogRequest{
-		ActivityLog:   todayLog.ActivityLog,
-		LessonLearned: todayLog.LessonLearned,
-		Obstacles:     todayLog.Obstacles,
-	})
+// checkInternshipPeriod reports whether today is inside the internship period
+// from the profile and remembers the end date in watch. It warns as the end
+// date approaches and returns errInternshipEnded once it has passed
+func checkInternshipPeriod(cfg *config.Config, user *api.User, watch *internshipWatch, today string) (bool, error) {
+	start, end, err := user.InternshipPeriod()
 	if err != nil {
-		log.Printf("SubmitAttendance error: %v", err)
-		return
+		log.Printf("⚠️  Internship period unknown (%v), not enforcing it", err)
+		return true, nil
+	}
+	watch.remember(end)
+
+	switch {
+	case today < start:
+		log.Printf("⏳ Internship starts on %s, skipping submission", start)
+		return false, nil
+	case today > end:
+		log.Printf("🏁 Internship ended on %s, skipping submission", end)
+		return false, fmt.Errorf("%w on %s", errInternshipEnded, end)
 	}
 
-	log.Printf("Attendance submitted: %s", response)
+	endDate, _ := time.Parse(api.DateFormat, end)
+	todayDate, _ := time.Parse(api.DateFormat, today)
+	daysLeft := int(endDate.Sub(todayDate).Hours() / 24)
+	if daysLeft <= cfg.InternshipEndWarningDays {
+		log.Printf("⚠️  Internship ends on %s (%d days left)", end, daysLeft)
+	}
+	return true, nil
 }
 
+const (
+	// verifyAttempts is how many times the submission is read back
+	verifyAttempts = 3
+	// verifyDelay is the pause between read-back attempts
+	verifyDelay = 5 * time.Second
+)
+
+// errNotVerified means the submitted attendance never showed up in the list
+var errNotVerified = errors.New("attendance not found after submit")
+
+// verifySubmission re-queries the attendance list until a record for date
+// with the expected status shows up
+func verifySubmission(ctx context.Context, apiClient *api.Client, date string, status api.Status) (*api.Attendance, error) {
+	var lastErr error
+	for attempt := 1; attempt <= verifyAttempts; attempt++ {
+		att, err := apiClient.FindAttendance(ctx, date)
+		switch {
+		case err != nil:
+			lastErr = err
+		case att == nil:
+			lastErr = errNotVerified
+		case att.Status != string(status):
+			lastErr = fmt.Errorf("attendance %s has status %s, expected %s", date, att.Status, status)
+		default:
+			return att, nil
+		}
+
+		log.Printf("🔎 Verification attempt %d/%d failed: %v", attempt, verifyAttempts, lastErr)
+		if attempt < verifyAttempts {
+			if err := sleepContext(ctx, verifyDelay); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, lastErr
+}
+
+const (
+	// loginRetryDelay is how long to wait after the browser login keeps failing
+	loginRetryDelay = 3 * time.Hour
+	// serviceRetryDelay is how long to wait when Monev is down or rate limiting
+	serviceRetryDelay = 30 * time.Minute
+)
+
+// errLoginFailed means the browser login failed every attempt
+var errLoginFailed = errors.New("login failed")
+
+// retryDelay returns how long to wait before running again after err, or
+// 0 when a retry would not help
+func retryDelay(err error) time.Duration {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return 0
+	case errors.Is(err, errLoginFailed):
+		return loginRetryDelay
+	case errors.Is(err, api.ErrUnauthorized), isServiceUnavailable(err), errors.Is(err, errNotVerified):
+		return serviceRetryDelay
+	}
+	return 0
+}
+
+// isServiceUnavailable reports whether the error is Monev's fault rather than ours
+func isServiceUnavailable(err error) bool {
+	return errors.Is(err, api.ErrServer) || errors.Is(err, api.ErrRateLimited) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// handleAPIError explains an API failure and decides whether a retry makes sense
+func handleAPIError(ctx context.Context, cfg *config.Config, sess *session, scheduler *schedule.Scheduler, err error) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		log.Println("🛑 Attendance run cancelled")
+	case errors.Is(err, api.ErrUnauthorized):
+		log.Println("🔑 Session expired, clearing cached cookies")
+		sess.cookies.Clear()
+	case isServiceUnavailable(err):
+		log.Println("🌐 Monev is unavailable, will try again later")
+	case errors.Is(err, api.ErrValidation):
+		log.Printf("📝 Daily log rejected by Monev, please fix %s", cfg.DailyLogsFile)
+	case errors.Is(err, api.ErrInvalidRequest):
+		log.Printf("📝 Daily log not sent, please fix %s", cfg.DailyLogsFile)
+	case errors.Is(err, errNotVerified):
+		log.Println("🔎 Submission did not show up yet, will check again later")
+	}
+	scheduleRetry(ctx, scheduler, retryDelay(err))
+}
+
+// scheduleRetry re-runs the attendance job after the given delay when a
+// scheduler is available; a zero delay means no retry
+func scheduleRetry(ctx context.Context, scheduler *schedule.Scheduler, after time.Duration) {
+	if scheduler == nil || after == 0 || ctx.Err() != nil {
+		return
+	}
+	scheduler.Retry(after)
+}
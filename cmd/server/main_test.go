@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want time.Duration
+	}{
+		{"login failed", fmt.Errorf("%w after 3 attempts: %w", errLoginFailed, errors.New("timeout")), loginRetryDelay},
+		{"session expired", &api.APIError{StatusCode: http.StatusUnauthorized}, serviceRetryDelay},
+		{"forbidden", &api.APIError{StatusCode: http.StatusForbidden}, serviceRetryDelay},
+		{"server error", &api.APIError{StatusCode: http.StatusBadGateway}, serviceRetryDelay},
+		{"rate limited", &api.APIError{StatusCode: http.StatusTooManyRequests}, serviceRetryDelay},
+		{"timeout", fmt.Errorf("get attendances: %w", context.DeadlineExceeded), serviceRetryDelay},
+		{"not verified", errNotVerified, serviceRetryDelay},
+		{"validation", &api.APIError{StatusCode: http.StatusUnprocessableEntity}, 0},
+		{"invalid request", fmt.Errorf("%w: activity_log is empty", api.ErrInvalidRequest), 0},
+		{"canceled", fmt.Errorf("shutting down: %w", context.Canceled), 0},
+		{"login canceled", fmt.Errorf("%w after 1 attempts: %w", errLoginFailed, context.Canceled), 0},
+		{"other", errors.New("daily logs file not found"), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryDelay(tt.err); got != tt.want {
+				t.Errorf("retryDelay(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
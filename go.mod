@@ -2,11 +2,15 @@ module maganghub-autopresence
 
 go 1.25.5
 
+require (
+	github.com/joho/godotenv v1.5.1
+	github.com/playwright-community/playwright-go v0.5200.1
+	github.com/robfig/cron/v3 v3.0.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
 require (
 	github.com/deckarep/golang-set/v2 v2.8.0 // indirect
 	github.com/go-jose/go-jose/v3 v3.0.4 // indirect
 	github.com/go-stack/stack v1.8.1 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
-	github.com/playwright-community/playwright-go v0.5200.1 // indirect
-	github.com/robfig/cron/v3 v3.0.1 // indirect
 )
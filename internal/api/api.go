@@ -1,33 +1,47 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"maganghub-autopresence/internal/metrics"
+
 	"github.com/playwright-community/playwright-go"
 )
 
-const MonevAPIURL = "https://monev.maganghub.kemnaker.go.id/api"
-
 // Client handles API requests with authentication
 type Client struct {
 	httpClient    *http.Client
+	baseURL       string
 	cookies       []playwright.Cookie
 	participantID string
+	dryRun        bool
 }
 
-// NewClient creates a new API client with cookies from playwright
-func NewClient(cookies []playwright.Cookie) *Client {
+// NewClient creates a new API client with cookies from playwright.
+// baseURL is the Monev site root, e.g. https://monev.maganghub.kemnaker.go.id,
+// and timeout bounds every request regardless of the caller's context
+func NewClient(baseURL string, timeout time.Duration, cookies []playwright.Cookie) *Client {
 	return &Client{
-		httpClient: &http.Client{},
+		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    strings.TrimRight(baseURL, "/"),
 		cookies:    cookies,
 	}
 }
 
+// apiURL returns the full URL for an API path
+func (c *Client) apiURL(path string) string {
+	return c.baseURL + "/api" + path
+}
+
 // formatCookies converts playwright cookies to a cookie header string
 func (c *Client) formatCookies() string {
 	var cookieParts []string
@@ -51,7 +65,7 @@ func (c *Client) getAccessToken() string {
 func (c *Client) setCommonHeaders(req *http.Request) {
 	req.Header.Set("accept", "application/json")
 	req.Header.Set("accept-language", "en-US,en;q=0.9")
-	req.Header.Set("referer", "https://monev.maganghub.kemnaker.go.id/dashboard")
+	req.Header.Set("referer", c.baseURL+"/dashboard")
 	req.Header.Set("user-agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36")
 	req.Header.Set("cookie", c.formatCookies())
 
@@ -61,100 +75,208 @@ func (c *Client) setCommonHeaders(req *http.Request) {
 	}
 }
 
-// GetMe fetches the current user profile and returns participant ID
-func (c *Client) GetMe() (*User, error) {
-	req, err := http.NewRequest("GET", MonevAPIURL+"/users/me", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	c.setCommonHeaders(req)
-
+// do sends the request and returns the body, or an *APIError for non-2xx responses
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	endpoint := endpointName(req)
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
+	metrics.APILatency.ObserveSince(start, endpoint)
 	if err != nil {
+		metrics.APIRequests.Inc(endpoint, "error")
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
+	metrics.APIRequests.Inc(endpoint, strconv.Itoa(resp.StatusCode))
 
 	bodyText, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	if err := checkStatus(resp, bodyText); err != nil {
+		return nil, err
+	}
+
+	return bodyText, nil
+}
+
+// endpointName labels a request for metrics by its API path, e.g.
+// "/users/me", leaving out the site root and the query
+func endpointName(req *http.Request) string {
+	if _, path, ok := strings.Cut(req.URL.Path, "/api/"); ok {
+		return "/" + path
+	}
+	return req.URL.Path
+}
+
+// GetMe fetches the current user profile and returns participant ID
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.apiURL("/users/me"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setCommonHeaders(req)
+
+	bodyText, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
 	var userResp UserResponse
 	if err := json.Unmarshal(bodyText, &userResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	if userResp.Data.ID == "" {
+		return nil, fmt.Errorf("%w: profile response has no user ID", ErrUnauthorized)
+	}
+
 	// Store participant ID for later use
 	c.participantID = userResp.Data.ID
 
 	return &userResp.Data, nil
 }
 
+// DryRun makes SubmitAttendance validate and return the JSON payload it
+// would have sent instead of POSTing it. Reads such as GetMe and
+// HasAttendedToday still go to the API
+func (c *Client) DryRun() {
+	c.dryRun = true
+}
+
 // SubmitAttendance submits attendance with daily log
-// Date is always today and Status is always PRESENT
-func (c *Client) SubmitAttendance(req DailyLogRequest) (string, error) {
-	// Date is always today
-	today := time.Now().Format("2006-01-02")
+// Date is always today and Status defaults to PRESENT.
+// After DryRun the returned response is the payload that would have been sent
+func (c *Client) SubmitAttendance(ctx context.Context, req DailyLogRequest) (string, error) {
+	// Date is always today in Monev's timezone
+	return c.SubmitAttendanceOn(ctx, Today(), req)
+}
+
+// SubmitAttendanceOn submits attendance with daily log for an explicit
+// date (YYYY-MM-DD), e.g. to backfill missed days. Future dates are rejected
+func (c *Client) SubmitAttendanceOn(ctx context.Context, date string, req DailyLogRequest) (string, error) {
+	if _, err := time.Parse(DateFormat, date); err != nil {
+		return "", fmt.Errorf("%w: date %q must be YYYY-MM-DD", ErrInvalidRequest, date)
+	}
+	// YYYY-MM-DD strings sort chronologically
+	if date > Today() {
+		return "", fmt.Errorf("%w: date %s is in the future", ErrInvalidRequest, date)
+	}
+
+	status := req.Status
+	if status == "" {
+		status = StatusPresent
+	}
 
-	data := fmt.Sprintf(
-		`{"date":"%s","status":"PRESENT","activity_log":"%s","lesson_learned":"%s","obstacles":"%s"}`,
-		today, req.ActivityLog, req.LessonLearned, req.Obstacles,
-	)
+	payload := attendanceRequest{
+		Date:          date,
+		Status:        string(status),
+		ActivityLog:   req.ActivityLog,
+		LessonLearned: req.LessonLearned,
+		Obstacles:     req.Obstacles,
+	}
 
-	httpReq, err := http.NewRequest("POST", MonevAPIURL+"/attendances/with-daily-log", strings.NewReader(data))
+	data, err := encodeAttendanceRequest(payload)
+	if err != nil {
+		return "", err
+	}
+
+	if c.dryRun {
+		return string(data), nil
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.apiURL("/attendances/with-daily-log"), bytes.NewReader(data))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	c.setCommonHeaders(httpReq)
 	httpReq.Header.Set("content-type", "application/json")
-	httpReq.Header.Set("origin", "https://monev.maganghub.kemnaker.go.id")
+	httpReq.Header.Set("origin", c.baseURL)
 
-	resp, err := c.httpClient.Do(httpReq)
+	bodyText, err := c.do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
 
-	bodyText, err := io.ReadAll(resp.Body)
+	return string(bodyText), nil
+}
+
+// maxAttendancePages guards against a server that ignores the page parameter
+const maxAttendancePages = 100
+
+// attendancePageSize is how many records are requested per page
+const attendancePageSize = 50
+
+// GetAttendances fetches attendance records for the current month
+func (c *Client) GetAttendances(ctx context.Context) (*AttendanceResponse, error) {
+	now := time.Now().In(Location)
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, Location)
+	to := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, Location)
+
+	attendances, err := c.GetAttendancesRange(ctx, from, to)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
-	return string(bodyText), nil
+	return &AttendanceResponse{
+		Data:     attendances,
+		Page:     1,
+		PageSize: len(attendances),
+		Total:    len(attendances),
+	}, nil
 }
 
-// GetAttendances fetches attendance records for the current month
-func (c *Client) GetAttendances() (*AttendanceResponse, error) {
+// GetAttendancesRange fetches every attendance record between from and to
+// (inclusive, by calendar date), following pagination until all are read
+func (c *Client) GetAttendancesRange(ctx context.Context, from, to time.Time) ([]Attendance, error) {
 	if c.participantID == "" {
 		return nil, fmt.Errorf("participant ID not set, call GetMe() first")
 	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid range: %s is before %s", to.Format(DateFormat), from.Format(DateFormat))
+	}
 
-	now := time.Now()
-	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
-	endDate := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+	startDate := from.Format(DateFormat)
+	endDate := to.Format(DateFormat)
 
-	url := fmt.Sprintf("%s/attendances?participant_id=%s&start_date=%s&end_date=%s",
-		MonevAPIURL, c.participantID, startDate, endDate)
+	var all []Attendance
+	for page := 1; page <= maxAttendancePages; page++ {
+		resp, err := c.getAttendancesPage(ctx, startDate, endDate, page)
+		if err != nil {
+			return nil, err
+		}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		all = append(all, resp.Data...)
+		// Total may be missing from the response, so a short page also ends the list
+		if len(resp.Data) < attendancePageSize || (resp.Total > 0 && len(all) >= resp.Total) {
+			return all, nil
+		}
 	}
 
-	c.setCommonHeaders(req)
+	return nil, fmt.Errorf("attendance list for %s..%s exceeds %d pages", startDate, endDate, maxAttendancePages)
+}
 
-	resp, err := c.httpClient.Do(req)
+// getAttendancesPage fetches a single page of the attendance list
+func (c *Client) getAttendancesPage(ctx context.Context, startDate, endDate string, page int) (*AttendanceResponse, error) {
+	query := url.Values{}
+	query.Set("participant_id", c.participantID)
+	query.Set("start_date", startDate)
+	query.Set("end_date", endDate)
+	query.Set("page", strconv.Itoa(page))
+	query.Set("page_size", strconv.Itoa(attendancePageSize))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.apiURL("/attendances")+"?"+query.Encode(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	bodyText, err := io.ReadAll(resp.Body)
+	c.setCommonHeaders(req)
+
+	bodyText, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
 	var attendanceResp AttendanceResponse
@@ -166,18 +288,56 @@ func (c *Client) GetAttendances() (*AttendanceResponse, error) {
 }
 
 // HasAttendedToday checks if attendance has been submitted for today
-func (c *Client) HasAttendedToday() (bool, error) {
-	attendances, err := c.GetAttendances()
+func (c *Client) HasAttendedToday(ctx context.Context) (bool, error) {
+	att, err := c.FindAttendance(ctx, Today())
 	if err != nil {
 		return false, err
 	}
 
-	today := time.Now().Format("2006-01-02")
-	for _, att := range attendances.Data {
-		if att.Date == today {
-			return true, nil
+	return att != nil, nil
+}
+
+// FindAttendance returns the attendance record for date (YYYY-MM-DD),
+// or nil if there is none
+func (c *Client) FindAttendance(ctx context.Context, date string) (*Attendance, error) {
+	day, err := time.ParseInLocation(DateFormat, date, Location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", date, err)
+	}
+
+	attendances, err := c.GetAttendancesRange(ctx, day, day)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range attendances {
+		if attendances[i].Date == date {
+			return &attendances[i], nil
 		}
 	}
 
-	return false, nil
+	return nil, nil
+}
+
+// encodeAttendanceRequest marshals the payload and round-trips it to make
+// sure the server receives exactly the text that was resolved
+func encodeAttendanceRequest(payload attendanceRequest) ([]byte, error) {
+	if err := payload.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	var decoded attendanceRequest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("payload is not valid JSON: %w", err)
+	}
+	if decoded != payload {
+		return nil, fmt.Errorf("payload changed after encoding, refusing to submit")
+	}
+
+	return data, nil
 }
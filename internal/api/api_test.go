@@ -0,0 +1,229 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+	"maganghub-autopresence/internal/fakemonev"
+)
+
+var testUser = api.User{ID: "participant-1", Name: "Fake User"}
+
+// newClient returns a client logged in to a fresh fake Monev server
+func newClient(t *testing.T) (*api.Client, *fakemonev.Server) {
+	t.Helper()
+	fake := fakemonev.New(testUser)
+	t.Cleanup(fake.Close)
+
+	client := api.NewClient(fake.URL(), 5*time.Second, fake.Cookies())
+	if _, err := client.GetMe(context.Background()); err != nil {
+		t.Fatalf("GetMe: %v", err)
+	}
+	return client, fake
+}
+
+// pastDate returns the date daysAgo days before today in Monev's timezone
+func pastDate(daysAgo int) string {
+	return time.Now().In(api.Location).AddDate(0, 0, -daysAgo).Format(api.DateFormat)
+}
+
+func TestSubmitAttendanceKeepsTextIntact(t *testing.T) {
+	tests := []struct {
+		name string
+		req  api.DailyLogRequest
+	}{
+		{
+			name: "quotes and backslashes",
+			req: api.DailyLogRequest{
+				ActivityLog:   `Menulis "handler" baru dan path C:\Users\intern\app`,
+				LessonLearned: `Escape \" di JSON dan 'tanda kutip tunggal'`,
+				Obstacles:     `Regex \d+\.\d+ sempat salah`,
+			},
+		},
+		{
+			name: "newlines and tabs",
+			req: api.DailyLogRequest{
+				ActivityLog:   "Baris pertama\nBaris kedua\r\nBaris ketiga",
+				LessonLearned: "Kolom\tdipisah\ttab",
+				Obstacles:     "Tidak ada.\n",
+			},
+		},
+		{
+			name: "non-ASCII text",
+			req: api.DailyLogRequest{
+				ActivityLog:   "Rapat dengan tim café ☕ dan review PR 👍",
+				LessonLearned: "日本語のドキュメントを読んだ — ternyata berguna",
+				Obstacles:     "Karakter \u2028 pemisah baris dan \u00a0 spasi",
+			},
+		},
+		{
+			name: "HTML-like text",
+			req: api.DailyLogRequest{
+				ActivityLog:   "Memperbaiki <script>alert(1)</script> & escaping",
+				LessonLearned: "a < b && c > d",
+				Obstacles:     "</textarea>",
+			},
+		},
+	}
+
+	client, fake := newClient(t)
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date := pastDate(i + 1)
+			if _, err := client.SubmitAttendanceOn(context.Background(), date, tt.req); err != nil {
+				t.Fatalf("SubmitAttendanceOn: %v", err)
+			}
+
+			subs := fake.Submissions()
+			got := subs[len(subs)-1]
+			want := fakemonev.Submission{
+				Date:          date,
+				Status:        string(api.StatusPresent),
+				ActivityLog:   tt.req.ActivityLog,
+				LessonLearned: tt.req.LessonLearned,
+				Obstacles:     tt.req.Obstacles,
+			}
+			if got != want {
+				t.Errorf("server received %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSubmitAttendanceRejectsInvalidRequests(t *testing.T) {
+	valid := api.DailyLogRequest{ActivityLog: "Kerja.", LessonLearned: "Belajar.", Obstacles: "Tidak ada."}
+	with := func(change func(*api.DailyLogRequest)) api.DailyLogRequest {
+		req := valid
+		change(&req)
+		return req
+	}
+
+	tests := []struct {
+		name string
+		date string
+		req  api.DailyLogRequest
+		want string
+	}{
+		{"NUL byte", pastDate(1), with(func(r *api.DailyLogRequest) { r.ActivityLog = "a\x00b" }), "activity_log contains control character U+0000"},
+		{"escape character", pastDate(1), with(func(r *api.DailyLogRequest) { r.Obstacles = "\x1b[31mred" }), "obstacles contains control character U+001B"},
+		{"C1 control", pastDate(1), with(func(r *api.DailyLogRequest) { r.LessonLearned = "next\u0085line" }), "lesson_learned contains control character U+0085"},
+		{"invalid UTF-8", pastDate(1), with(func(r *api.DailyLogRequest) { r.ActivityLog = "caf\xe9" }), "activity_log contains invalid UTF-8"},
+		{"empty activity log", pastDate(1), with(func(r *api.DailyLogRequest) { r.ActivityLog = "" }), "activity_log is empty"},
+		{"blank lesson learned", pastDate(1), with(func(r *api.DailyLogRequest) { r.LessonLearned = " \n\t" }), "lesson_learned is empty"},
+		{"empty obstacles", pastDate(1), with(func(r *api.DailyLogRequest) { r.Obstacles = "" }), "obstacles is empty"},
+		{"absence without a reason", pastDate(1), api.DailyLogRequest{Status: api.StatusSick}, "activity_log is empty"},
+		{"unknown status", pastDate(1), with(func(r *api.DailyLogRequest) { r.Status = "HOLIDAY" }), "HOLIDAY"},
+		{"malformed date", "20-10-2026", valid, "must be YYYY-MM-DD"},
+		{"future date", pastDate(-1), valid, "is in the future"},
+	}
+
+	client, fake := newClient(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.SubmitAttendanceOn(context.Background(), tt.date, tt.req)
+			if !errors.Is(err, api.ErrInvalidRequest) {
+				t.Fatalf("error = %v, want ErrInvalidRequest", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+	if n := len(fake.Submissions()); n != 0 {
+		t.Errorf("server received %d invalid submissions", n)
+	}
+}
+
+func TestSubmitAttendanceAbsenceNeedsOnlyAReason(t *testing.T) {
+	client, fake := newClient(t)
+	req := api.DailyLogRequest{Status: api.StatusSick, ActivityLog: "Demam, istirahat sesuai surat dokter."}
+	if _, err := client.SubmitAttendanceOn(context.Background(), pastDate(1), req); err != nil {
+		t.Fatalf("SubmitAttendanceOn: %v", err)
+	}
+	if subs := fake.Submissions(); len(subs) != 1 || subs[0].Status != string(api.StatusSick) {
+		t.Errorf("submissions = %+v, want one SICK", subs)
+	}
+}
+
+func TestDryRunReturnsThePayloadWithoutSending(t *testing.T) {
+	client, fake := newClient(t)
+	client.DryRun()
+
+	payload, err := client.SubmitAttendanceOn(context.Background(), pastDate(1), api.DailyLogRequest{
+		ActivityLog: "Kutip \" dan baris\nbaru", LessonLearned: "é", Obstacles: "-",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(payload, `"activity_log":"Kutip \" dan baris\nbaru"`) {
+		t.Errorf("payload = %s", payload)
+	}
+	if n := len(fake.Submissions()); n != 0 {
+		t.Errorf("dry run sent %d submissions", n)
+	}
+}
+
+func TestGetAttendancesRangePagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		records    int
+		omitTotal  bool
+		wantPages  []int
+		wantLength int
+	}{
+		{"empty", 0, false, []int{1}, 0},
+		{"one short page", 20, false, []int{1}, 20},
+		{"short last page without total", 2*api.AttendancePageSize + 20, true, []int{1, 2, 3}, 2*api.AttendancePageSize + 20},
+		{"exact multiple without total", 2 * api.AttendancePageSize, true, []int{1, 2, 3}, 2 * api.AttendancePageSize},
+		{"exact multiple with total", 2 * api.AttendancePageSize, false, []int{1, 2}, 2 * api.AttendancePageSize},
+	}
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, api.Location)
+	to := time.Date(2026, 12, 31, 0, 0, 0, 0, api.Location)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := newClient(t)
+			for i := 0; i < tt.records; i++ {
+				fake.AddAttendance(api.Attendance{Date: from.AddDate(0, 0, i).Format(api.DateFormat), Status: "PRESENT"})
+			}
+			if tt.omitTotal {
+				fake.OmitTotal()
+			}
+
+			got, err := client.GetAttendancesRange(context.Background(), from, to)
+			if err != nil {
+				t.Fatalf("GetAttendancesRange: %v", err)
+			}
+			if len(got) != tt.wantLength {
+				t.Errorf("got %d records, want %d", len(got), tt.wantLength)
+			}
+			if pages := fake.AttendancePages(); !slices.Equal(pages, tt.wantPages) {
+				t.Errorf("requested pages %v, want %v", pages, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestGetAttendancesRangeStopsAtThePageCap(t *testing.T) {
+	client, fake := newClient(t)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, api.Location)
+	for i := 0; i < api.AttendancePageSize; i++ {
+		fake.AddAttendance(api.Attendance{Date: from.AddDate(0, 0, i).Format(api.DateFormat), Status: "PRESENT"})
+	}
+	// Every page is full and there is no total, so only the cap ends the loop
+	fake.OmitTotal()
+	fake.IgnorePage()
+
+	_, err := client.GetAttendancesRange(context.Background(), from, from.AddDate(0, 11, 0))
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("error = %v, want the page cap", err)
+	}
+	if n := len(fake.AttendancePages()); n != api.MaxAttendancePages {
+		t.Errorf("requested %d pages, want %d", n, api.MaxAttendancePages)
+	}
+}
@@ -0,0 +1,24 @@
+package api
+
+import "time"
+
+// DateFormat is the date layout the Monev API uses
+const DateFormat = "2006-01-02"
+
+// Location is Monev's timezone. "Today" always means today in WIB,
+// whatever timezone the host machine runs in
+var Location = loadLocation()
+
+func loadLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		// No tzdata on the host, WIB has no DST so a fixed offset is exact
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// Today returns today's date in Monev's timezone as YYYY-MM-DD
+func Today() string {
+	return time.Now().In(Location).Format(DateFormat)
+}
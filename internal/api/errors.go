@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sentinel errors for the kinds of failures callers react to differently.
+// Use errors.Is to check an error returned by Client against them
+var (
+	// ErrUnauthorized means the session cookies are missing or expired
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited means Monev asked us to slow down
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer means Monev itself failed (5xx)
+	ErrServer = errors.New("server error")
+	// ErrValidation means Monev rejected the request content
+	ErrValidation = errors.New("validation failed")
+	// ErrInvalidRequest means the request was refused before it was sent,
+	// e.g. an empty field or a date in the future
+	ErrInvalidRequest = errors.New("invalid request")
+)
+
+// maxErrorBody caps how much of an error body is kept in APIError
+const maxErrorBody = 512
+
+// APIError is returned for any non-2xx response from Monev
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// Message is the server's error message if the body had one
+	Message string
+	// Body is the raw response body, truncated to maxErrorBody bytes
+	Body string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Body
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s: HTTP %d: %s", e.Method, e.Path, e.StatusCode, msg)
+}
+
+// Unwrap maps the status code to one of the sentinel errors
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case e.StatusCode >= 500:
+		return ErrServer
+	}
+	return nil
+}
+
+// checkStatus returns an *APIError if the response is not a 2xx
+func checkStatus(resp *http.Response, body []byte) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	raw := strings.TrimSpace(string(body))
+	if len(raw) > maxErrorBody {
+		// Back up to a rune boundary so the text stays valid UTF-8
+		cut := maxErrorBody
+		for cut > 0 && !utf8.RuneStart(raw[cut]) {
+			cut--
+		}
+		raw = raw[:cut] + "..."
+	}
+
+	return &APIError{
+		Method:     resp.Request.Method,
+		Path:       resp.Request.URL.Path,
+		StatusCode: resp.StatusCode,
+		Message:    errorMessage(body),
+		Body:       raw,
+	}
+}
+
+// errorMessage extracts the human-readable message from a JSON error body
+func errorMessage(body []byte) string {
+	var parsed struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return ""
+	}
+	if parsed.Message != "" {
+		return parsed.Message
+	}
+	return parsed.Error
+}
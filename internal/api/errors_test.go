@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// errorResponse returns a response for GET /api/me with the given status
+func errorResponse(status int) *http.Response {
+	return &http.Response{StatusCode: status, Request: httptest.NewRequest(http.MethodGet, "/api/me", nil)}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	sentinels := []error{ErrUnauthorized, ErrRateLimited, ErrValidation, ErrServer}
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusUnprocessableEntity, ErrValidation},
+		{http.StatusInternalServerError, ErrServer},
+		{http.StatusBadGateway, ErrServer},
+		{http.StatusServiceUnavailable, ErrServer},
+		{http.StatusNotFound, nil},
+		{http.StatusConflict, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			err := checkStatus(errorResponse(tt.status), nil)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Fatalf("checkStatus = %v, want an *APIError with status %d", err, tt.status)
+			}
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, sentinel, got)
+				}
+			}
+		})
+	}
+
+	if err := checkStatus(errorResponse(http.StatusNoContent), nil); err != nil {
+		t.Errorf("checkStatus(204) = %v, want nil", err)
+	}
+}
+
+func TestCheckStatusTruncatesOnARuneBoundary(t *testing.T) {
+	// "é" is two bytes and starts at byte maxErrorBody-1, so a plain cut at
+	// maxErrorBody would split it
+	body := strings.Repeat("a", maxErrorBody-1) + "é" + strings.Repeat("b", 10)
+
+	err := checkStatus(errorResponse(http.StatusBadGateway), []byte(body))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("checkStatus = %v, want an *APIError", err)
+	}
+	if !utf8.ValidString(apiErr.Body) {
+		t.Errorf("Body is not valid UTF-8: %q", apiErr.Body[len(apiErr.Body)-8:])
+	}
+	if want := strings.Repeat("a", maxErrorBody-1) + "..."; apiErr.Body != want {
+		t.Errorf("Body ends in %q, want it cut before the é", apiErr.Body[len(apiErr.Body)-8:])
+	}
+
+	short := strings.Repeat("é", 10)
+	if err := checkStatus(errorResponse(http.StatusBadGateway), []byte(short)); err.(*APIError).Body != short {
+		t.Errorf("short body was changed: %q", err.(*APIError).Body)
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"message field", `{"message":"tanggal sudah diisi"}`, "GET /api/me: HTTP 422: tanggal sudah diisi"},
+		{"error field", `{"error":"invalid token"}`, "GET /api/me: HTTP 422: invalid token"},
+		{"message wins over error", `{"message":"a","error":"b"}`, "GET /api/me: HTTP 422: a"},
+		{"plain text body", "upstream timed out", "GET /api/me: HTTP 422: upstream timed out"},
+		{"empty body", "", "GET /api/me: HTTP 422: Unprocessable Entity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStatus(errorResponse(http.StatusUnprocessableEntity), []byte(tt.body))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
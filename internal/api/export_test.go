@@ -0,0 +1,7 @@
+package api
+
+// Exported for the api_test package
+const (
+	AttendancePageSize = attendancePageSize
+	MaxAttendancePages = maxAttendancePages
+)
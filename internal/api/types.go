@@ -1,7 +1,38 @@
 package api
 
-// DailyLogRequest represents the customizable fields for attendance submission
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Status is an attendance status accepted by Monev
+type Status string
+
+const (
+	StatusPresent Status = "PRESENT"
+	StatusSick    Status = "SICK"
+	StatusPermit  Status = "PERMIT"
+	StatusLeave   Status = "LEAVE"
+)
+
+// ParseStatus converts a case-insensitive status name into a Status
+func ParseStatus(value string) (Status, error) {
+	status := Status(strings.ToUpper(strings.TrimSpace(value)))
+	switch status {
+	case StatusPresent, StatusSick, StatusPermit, StatusLeave:
+		return status, nil
+	}
+	return "", fmt.Errorf("unknown attendance status %q", value)
+}
+
+// DailyLogRequest represents the customizable fields for attendance submission.
+// Status defaults to PRESENT; for other statuses ActivityLog carries the reason
+// and the remaining fields may be empty
 type DailyLogRequest struct {
+	Status        Status
 	ActivityLog   string
 	LessonLearned string
 	Obstacles     string
@@ -49,7 +80,86 @@ type User struct {
 	UpdatedAt                    string `json:"updated_at"`
 }
 
+// InternshipPeriod returns the internship start and end dates as YYYY-MM-DD.
+// The API may send plain dates or full timestamps, only the date part is used
+func (u *User) InternshipPeriod() (start, end string, err error) {
+	start, err = profileDate(u.InternshipStartDate)
+	if err != nil {
+		return "", "", fmt.Errorf("internship_start_date: %w", err)
+	}
+	end, err = profileDate(u.InternshipEndDate)
+	if err != nil {
+		return "", "", fmt.Errorf("internship_end_date: %w", err)
+	}
+	if end < start {
+		return "", "", fmt.Errorf("internship ends (%s) before it starts (%s)", end, start)
+	}
+	return start, end, nil
+}
+
+func profileDate(value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("not set")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(Location).Format(DateFormat), nil
+	}
+	if len(value) >= len(DateFormat) {
+		if t, err := time.Parse(DateFormat, value[:len(DateFormat)]); err == nil {
+			return t.Format(DateFormat), nil
+		}
+	}
+	return "", fmt.Errorf("unrecognised date %q", value)
+}
+
 // UserResponse represents the API response for user profile
 type UserResponse struct {
 	Data User `json:"data"`
 }
+
+// attendanceRequest is the JSON body sent to /attendances/with-daily-log
+type attendanceRequest struct {
+	Date          string `json:"date"`
+	Status        string `json:"status"`
+	ActivityLog   string `json:"activity_log"`
+	LessonLearned string `json:"lesson_learned"`
+	Obstacles     string `json:"obstacles"`
+}
+
+// validate rejects field content that would not survive a JSON round trip
+// or that the Monev form would never accept from a user
+func (r attendanceRequest) validate() error {
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"activity_log", r.ActivityLog},
+		{"lesson_learned", r.LessonLearned},
+		{"obstacles", r.Obstacles},
+	}
+
+	if _, err := ParseStatus(r.Status); err != nil {
+		return err
+	}
+
+	for i, f := range fields {
+		// Only a presence needs the full log, absences just carry a reason
+		required := r.Status == string(StatusPresent) || i == 0
+		if required && strings.TrimSpace(f.value) == "" {
+			return fmt.Errorf("%s is empty", f.name)
+		}
+		if !utf8.ValidString(f.value) {
+			return fmt.Errorf("%s contains invalid UTF-8", f.name)
+		}
+		for i, r := range f.value {
+			if r == '\n' || r == '\r' || r == '\t' {
+				continue
+			}
+			if unicode.IsControl(r) {
+				return fmt.Errorf("%s contains control character %U at byte %d", f.name, r, i)
+			}
+		}
+	}
+
+	return nil
+}
@@ -3,25 +3,31 @@ package browser
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/playwright-community/playwright-go"
-)
+	"maganghub-autopresence/internal/metrics"
 
-const (
-	MaganghubAuthURL  = "https://account.kemnaker.go.id/auth/login"
-	MonevDashboardURL = "https://monev.maganghub.kemnaker.go.id/dashboard"
+	"github.com/playwright-community/playwright-go"
 )
 
 // BrowserClient encapsulates the browser state
 type BrowserClient struct {
-	pw      *playwright.Playwright
-	Browser playwright.Browser
-	Context playwright.BrowserContext
-	Page    playwright.Page
+	pw           *playwright.Playwright
+	authURL      string
+	dashboardURL string
+	Browser      playwright.Browser
+	Context      playwright.BrowserContext
+	Page         playwright.Page
+
+	closeOnce sync.Once
+	closeErr  error
 }
 
-// NewBrowserClient initializes a new browser client
-func NewBrowserClient(headless bool) (*BrowserClient, error) {
+// NewBrowserClient initializes a new browser client.
+// authURL is the SSO login page and monevURL the Monev site root
+func NewBrowserClient(headless bool, authURL, monevURL string) (*BrowserClient, error) {
 	pw, err := playwright.Run()
 	if err != nil {
 		return nil, fmt.Errorf("could not start playwright: %w", err)
@@ -35,13 +41,26 @@ func NewBrowserClient(headless bool) (*BrowserClient, error) {
 	}
 
 	return &BrowserClient{
-		pw:      pw,
-		Browser: browser,
+		pw:           pw,
+		authURL:      authURL,
+		dashboardURL: strings.TrimRight(monevURL, "/") + "/dashboard",
+		Browser:      browser,
 	}, nil
 }
 
 // Login performs authentication and returns the logged-in user name
-func (c *BrowserClient) Login(username, password string) (string, error) {
+func (c *BrowserClient) Login(username, password string) (name string, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.LoginDuration.ObserveSince(start)
+		if err != nil {
+			metrics.Logins.Inc("failure")
+			return
+		}
+		metrics.Logins.Inc("success")
+		metrics.LastLogin.SetTime(time.Now())
+	}()
+
 	page, err := c.Browser.NewPage()
 	if err != nil {
 		return "", fmt.Errorf("could not create page: %w", err)
@@ -49,7 +68,7 @@ func (c *BrowserClient) Login(username, password string) (string, error) {
 	c.Page = page
 	c.Context = c.Browser.Contexts()[0]
 
-	if _, err = page.Goto(MonevDashboardURL); err != nil {
+	if _, err = page.Goto(c.dashboardURL); err != nil {
 		return "", fmt.Errorf("could not navigate: %w", err)
 	}
 
@@ -60,7 +79,7 @@ func (c *BrowserClient) Login(username, password string) (string, error) {
 	}
 
 	// Check if redirected to auth page
-	if page.URL() != MaganghubAuthURL {
+	if page.URL() != c.authURL {
 		return "", errors.New("not redirected to auth page")
 	}
 
@@ -98,8 +117,16 @@ func (c *BrowserClient) GetCookies() ([]playwright.Cookie, error) {
 	return c.Context.Cookies()
 }
 
-// Close cleans up all browser resources
+// Close cleans up all browser resources. It is safe to call more than once,
+// e.g. from a cancellation hook while Login is still running
 func (c *BrowserClient) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.close()
+	})
+	return c.closeErr
+}
+
+func (c *BrowserClient) close() error {
 	if c.Browser != nil {
 		if err := c.Browser.Close(); err != nil {
 			return err
@@ -3,19 +3,80 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+const (
+	DefaultMonevURL = "https://monev.maganghub.kemnaker.go.id"
+	DefaultAuthURL  = "https://account.kemnaker.go.id/auth/login"
+)
+
 type Config struct {
 	MaganghubConfig MaganghubConfig
 	CronSchedule    string
-	Headless        bool
+	// RunWindow is an optional "HH:MM-HH:MM" range; each day's run time is drawn from it
+	RunWindow string
+	// RunWindowSeed varies the drawn times between users
+	RunWindowSeed string
+	Headless      bool
+	DailyLogsFile string
+	// CookiesFile caches the session cookies between runs
+	CookiesFile string
+	// HistoryFile is the JSONL file every attendance run is recorded in
+	HistoryFile string
+	// LogRepeatWindowDays avoids resubmitting a daily log variant within this many days
+	LogRepeatWindowDays int
+	// RunStateFile stores the date of the last successful run
+	RunStateFile string
+	// CatchUpGrace is how late a missed run may still be caught up
+	CatchUpGrace time.Duration
+	// LogSources lists where daily logs come from, tried in order:
+	// "file" (DailyLogsFile), "dir" (DailyLogsDir) and "command" (LogCommand)
+	LogSources []string
+	// DailyLogsDir holds one daily log file per date, e.g. 2026-10-20.md
+	DailyLogsDir string
+	// LogCommand prints a daily log as JSON for the "command" source
+	LogCommand string
+	// LogCommandTimeout bounds LogCommand
+	LogCommandTimeout time.Duration
+	// GitRepos are local repositories whose commits become the activity log
+	GitRepos []string
+	// GitLogAuthor filters commits; empty uses each repo's user.email
+	GitLogAuthor string
+	// GitSummaryTemplateFile overrides the commit summary template
+	GitSummaryTemplateFile string
+	// ValidationRulesFile configures the daily log validation rules
+	ValidationRulesFile string
+	// LeaveFile lists planned absences (sick, permit, leave) by date
+	LeaveFile string
+	// SkipHolidays disables submission on public holidays and cuti bersama
+	SkipHolidays bool
+	// HolidayFile overrides the bundled holiday calendar (.json or .ics)
+	HolidayFile string
+	// InternshipEndWarningDays is how many days before the internship ends to start warning
+	InternshipEndWarningDays int
+	// APITimeout bounds each request to the Monev API
+	APITimeout time.Duration
+	// RunTimeout bounds a whole attendance run, including browser login
+	RunTimeout time.Duration
+	// AdminAddr is where the admin API and dashboard listen, e.g.
+	// 127.0.0.1:8080; empty disables them
+	AdminAddr string
+	// AdminToken, when set, is required by the admin API
+	AdminToken string
+	// DryRun records the attendance payload instead of submitting it
+	DryRun bool
 }
 
 type MaganghubConfig struct {
 	Username string
 	Password string
+	MonevURL string
+	AuthURL  string
 }
 
 func Load() *Config {
@@ -29,15 +90,106 @@ func Load() *Config {
 		cronSchedule = "0 8 * * 1-5" // Default: 8am on weekdays
 	}
 
+	runWindowSeed := os.Getenv("RUN_WINDOW_SEED")
+	if runWindowSeed == "" {
+		runWindowSeed = os.Getenv("MAGANGHUB_USERNAME")
+	}
+
 	headless := os.Getenv("HEADLESS") != "false" // Default: true
 
+	monevURL := os.Getenv("MONEV_URL")
+	if monevURL == "" {
+		monevURL = DefaultMonevURL
+	}
+
+	authURL := os.Getenv("MAGANGHUB_AUTH_URL")
+	if authURL == "" {
+		authURL = DefaultAuthURL
+	}
+
+	dailyLogsFile := os.Getenv("DAILY_LOGS_FILE")
+	if dailyLogsFile == "" {
+		dailyLogsFile = DetectDailyLogsFile()
+	}
+
+	logSources := splitList(os.Getenv("LOG_SOURCES"))
+	if len(logSources) == 0 {
+		logSources = []string{"file"}
+	}
+
+	dailyLogsDir := os.Getenv("DAILY_LOGS_DIR")
+	if dailyLogsDir == "" {
+		dailyLogsDir = "daily_logs.d"
+	}
+
+	validationRulesFile := os.Getenv("VALIDATION_RULES_FILE")
+	if validationRulesFile == "" {
+		validationRulesFile = "validation_rules.json"
+	}
+
+	leaveFile := os.Getenv("LEAVE_FILE")
+	if leaveFile == "" {
+		leaveFile = "leave.json"
+	}
+
+	skipHolidays := os.Getenv("SKIP_HOLIDAYS") != "false" // Default: true
+
+	endWarningDays := getInt("INTERNSHIP_END_WARNING_DAYS", 14)
+
+	cookiesFile := os.Getenv("COOKIES_FILE")
+	if cookiesFile == "" {
+		cookiesFile = "cookies.json"
+	}
+
+	historyFile := os.Getenv("HISTORY_FILE")
+	if historyFile == "" {
+		historyFile = "history.jsonl"
+	}
+
+	runStateFile := os.Getenv("RUN_STATE_FILE")
+	if runStateFile == "" {
+		runStateFile = "run_state.json"
+	}
+
+	dryRun := os.Getenv("DRY_RUN") == "true" // Default: false
+
+	apiTimeout := getDuration("API_TIMEOUT", 30*time.Second)
+	runTimeout := getDuration("RUN_TIMEOUT", 10*time.Minute)
+
 	cfg := &Config{
 		MaganghubConfig: MaganghubConfig{
 			Username: os.Getenv("MAGANGHUB_USERNAME"),
 			Password: os.Getenv("MAGANGHUB_PASSWORD"),
+			MonevURL: monevURL,
+			AuthURL:  authURL,
 		},
-		CronSchedule: cronSchedule,
-		Headless:     headless,
+		CronSchedule:             cronSchedule,
+		RunWindow:                os.Getenv("RUN_WINDOW"),
+		RunWindowSeed:            runWindowSeed,
+		Headless:                 headless,
+		DailyLogsFile:            dailyLogsFile,
+		LogSources:               logSources,
+		DailyLogsDir:             dailyLogsDir,
+		LogCommand:               os.Getenv("LOG_COMMAND"),
+		LogCommandTimeout:        getDuration("LOG_COMMAND_TIMEOUT", 30*time.Second),
+		GitRepos:                 splitList(os.Getenv("GIT_REPOS")),
+		GitLogAuthor:             os.Getenv("GIT_LOG_AUTHOR"),
+		GitSummaryTemplateFile:   os.Getenv("GIT_SUMMARY_TEMPLATE_FILE"),
+		ValidationRulesFile:      validationRulesFile,
+		LeaveFile:                leaveFile,
+		CookiesFile:              cookiesFile,
+		HistoryFile:              historyFile,
+		LogRepeatWindowDays:      getInt("LOG_REPEAT_WINDOW_DAYS", 7),
+		RunStateFile:             runStateFile,
+		CatchUpGrace:             getDuration("CATCH_UP_GRACE", 4*time.Hour),
+		SkipHolidays:             skipHolidays,
+		HolidayFile:              os.Getenv("HOLIDAY_FILE"),
+		APITimeout:               apiTimeout,
+		RunTimeout:               runTimeout,
+		InternshipEndWarningDays: endWarningDays,
+		DryRun:                   dryRun,
+		AdminAddr:                os.Getenv("ADMIN_ADDR"),
+		AdminToken:               os.Getenv("ADMIN_TOKEN"),
 	}
 
 	if cfg.MaganghubConfig.Username == "" || cfg.MaganghubConfig.Password == "" {
@@ -46,3 +198,61 @@ func Load() *Config {
 
 	return cfg
 }
+
+// dailyLogsCandidates are the daily log files looked for, in order,
+// when DAILY_LOGS_FILE is unset
+var dailyLogsCandidates = []string{"daily_logs.json", "daily_logs.yaml", "daily_logs.yml", "daily_logs.md"}
+
+// DetectDailyLogsFile returns the first daily log file that exists in the
+// working directory, or daily_logs.json when there is none
+func DetectDailyLogsFile() string {
+	for _, name := range dailyLogsCandidates {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return dailyLogsCandidates[0]
+}
+
+// splitList splits a comma-separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getInt reads a non-negative integer from the environment,
+// falling back to def when the variable is unset or invalid
+func getInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("Warning: invalid %s %q, using default %d", key, value, def)
+		return def
+	}
+	return n
+}
+
+// getDuration reads a duration such as "30s" or "5m" from the environment,
+// falling back to def when the variable is unset or invalid
+func getDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Warning: invalid %s %q, using default %s", key, value, def)
+		return def
+	}
+	return d
+}
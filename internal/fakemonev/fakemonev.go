@@ -0,0 +1,263 @@
+// Package fakemonev provides an in-memory stand-in for the Monev API so the
+// attendance pipeline can be exercised without touching the real service.
+package fakemonev
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// AccessToken is the token the fake server accepts by default
+const AccessToken = "fake-access-token"
+
+// defaultPageSize is used when the client does not send page_size
+const defaultPageSize = 50
+
+// Submission is a daily log body received by the fake server
+type Submission struct {
+	Date          string `json:"date"`
+	Status        string `json:"status"`
+	ActivityLog   string `json:"activity_log"`
+	LessonLearned string `json:"lesson_learned"`
+	Obstacles     string `json:"obstacles"`
+}
+
+// Server is an httptest-backed fake of the Monev API
+type Server struct {
+	srv *httptest.Server
+
+	mu          sync.Mutex
+	user        api.User
+	attendances []api.Attendance
+	submissions []Submission
+	nextID      int
+
+	omitTotal  bool
+	ignorePage bool
+	pages      []int
+}
+
+// New starts a fake Monev server that serves the given user profile
+func New(user api.User) *Server {
+	s := &Server{
+		user:   user,
+		nextID: 1,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/users/me", s.handleMe)
+	mux.HandleFunc("GET /api/attendances", s.handleAttendances)
+	mux.HandleFunc("POST /api/attendances/with-daily-log", s.handleSubmit)
+
+	s.srv = httptest.NewServer(s.authorize(mux))
+	return s
+}
+
+// URL returns the Monev site root to pass to api.NewClient
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Cookies returns cookies that the fake server accepts as a logged-in session
+func (s *Server) Cookies() []playwright.Cookie {
+	return []playwright.Cookie{
+		{Name: "accessToken", Value: AccessToken, Domain: "127.0.0.1", Path: "/"},
+	}
+}
+
+// AddAttendance seeds an existing attendance record
+func (s *Server) AddAttendance(att api.Attendance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if att.ID == 0 {
+		att.ID = s.nextID
+	}
+	if att.ID >= s.nextID {
+		s.nextID = att.ID + 1
+	}
+	if att.ParticipantID == "" {
+		att.ParticipantID = s.user.ID
+	}
+	s.attendances = append(s.attendances, att)
+}
+
+// OmitTotal makes attendance list responses leave out the total count
+func (s *Server) OmitTotal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.omitTotal = true
+}
+
+// IgnorePage makes the attendance list serve the first page whatever page
+// is asked for, like a server without pagination
+func (s *Server) IgnorePage() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ignorePage = true
+}
+
+// AttendancePages returns the page numbers asked for by attendance list
+// requests, in order
+func (s *Server) AttendancePages() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.pages...)
+}
+
+// Attendances returns a copy of all stored attendance records
+func (s *Server) Attendances() []api.Attendance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]api.Attendance(nil), s.attendances...)
+}
+
+// Submissions returns a copy of all daily log bodies received so far
+func (s *Server) Submissions() []Submission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Submission(nil), s.submissions...)
+}
+
+// authorize rejects requests without the expected bearer token
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("authorization") != "Bearer "+AccessToken {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, api.UserResponse{Data: s.user})
+}
+
+func (s *Server) handleAttendances(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	participantID := q.Get("participant_id")
+	startDate := q.Get("start_date")
+	endDate := q.Get("end_date")
+
+	page, err := strconv.Atoi(q.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(q.Get("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+
+	s.mu.Lock()
+	s.pages = append(s.pages, page)
+	omitTotal := s.omitTotal
+	if s.ignorePage {
+		page = 1
+	}
+	var matched []api.Attendance
+	for _, att := range s.attendances {
+		if participantID != "" && att.ParticipantID != participantID {
+			continue
+		}
+		// Dates are YYYY-MM-DD so string comparison orders them correctly
+		if startDate != "" && att.Date < startDate {
+			continue
+		}
+		if endDate != "" && att.Date > endDate {
+			continue
+		}
+		matched = append(matched, att)
+	}
+	s.mu.Unlock()
+
+	start := min((page-1)*pageSize, len(matched))
+	end := min(start+pageSize, len(matched))
+
+	total := len(matched)
+	if omitTotal {
+		total = 0
+	}
+	writeJSON(w, http.StatusOK, api.AttendanceResponse{
+		Data:     matched[start:end],
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var sub Submission
+	if err := json.NewDecoder(r.Body).Decode(&sub); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if _, err := time.Parse("2006-01-02", sub.Date); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "date must be YYYY-MM-DD")
+		return
+	}
+	if _, err := api.ParseStatus(sub.Status); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if strings.TrimSpace(sub.ActivityLog) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "activity_log is required")
+		return
+	}
+	if sub.Status == string(api.StatusPresent) && (strings.TrimSpace(sub.LessonLearned) == "" || strings.TrimSpace(sub.Obstacles) == "") {
+		writeError(w, http.StatusUnprocessableEntity, "daily log fields are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, att := range s.attendances {
+		if att.ParticipantID == s.user.ID && att.Date == sub.Date {
+			writeError(w, http.StatusConflict, "attendance already submitted for this date")
+			return
+		}
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	att := api.Attendance{
+		ID:             s.nextID,
+		ParticipantID:  s.user.ID,
+		Date:           sub.Date,
+		Status:         sub.Status,
+		ApprovalStatus: "PENDING",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	s.nextID++
+	s.attendances = append(s.attendances, att)
+	s.submissions = append(s.submissions, sub)
+
+	writeJSON(w, http.StatusCreated, map[string]any{"data": att})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
@@ -0,0 +1,107 @@
+// Package gitlog reads the intern's own commits from local git repositories
+// so the activity log can describe the work that was actually done.
+package gitlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Commit is a single commit found in one of the repositories
+type Commit struct {
+	// Repo is the repository directory name, e.g. "backend-api"
+	Repo    string
+	Hash    string
+	Subject string
+	Time    time.Time
+}
+
+// Field and record separators for git log --format
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// Commits returns the non-merge commits on any branch of repos authored by
+// the email address author during date's calendar day (in date's
+// location), oldest first. An empty author uses each repository's
+// configured user.email
+func Commits(ctx context.Context, repos []string, author string, date time.Time) ([]Commit, error) {
+	since := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	until := since.AddDate(0, 0, 1)
+
+	var commits []Commit
+	seen := make(map[string]bool)
+	for _, repo := range repos {
+		email := author
+		if email == "" {
+			out, err := git(ctx, repo, "config", "user.email")
+			if err != nil {
+				return nil, fmt.Errorf("%s: user.email tidak diset, isi GIT_LOG_AUTHOR: %w", repo, err)
+			}
+			email = strings.TrimSpace(out)
+		}
+
+		// The author is compared here rather than with --author, which
+		// takes a regular expression and matches names and substrings too
+		out, err := git(ctx, repo, "log", "--all", "--no-merges", "--reverse",
+			"--since="+since.Format(time.RFC3339),
+			"--until="+until.Format(time.RFC3339),
+			"--format=%H"+fieldSep+"%ae"+fieldSep+"%aI"+fieldSep+"%s"+recordSep,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", repo, err)
+		}
+
+		name := repoName(repo)
+		for _, record := range strings.Split(out, recordSep) {
+			fields := strings.Split(strings.TrimSpace(record), fieldSep)
+			if len(fields) != 4 || !strings.EqualFold(fields[1], email) {
+				continue
+			}
+			// The same commit can be reachable from several repos' clones
+			if seen[fields[0]] {
+				continue
+			}
+			seen[fields[0]] = true
+
+			at, err := time.Parse(time.RFC3339, fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("%s: tanggal commit %q tidak valid: %w", repo, fields[2], err)
+			}
+			commits = append(commits, Commit{Repo: name, Hash: fields[0], Subject: fields[3], Time: at})
+		}
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].Time.Before(commits[j].Time) })
+	return commits, nil
+}
+
+// git runs a git command inside repo and returns its stdout
+func git(ctx context.Context, repo string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repo}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// repoName returns the directory name of repo
+func repoName(repo string) string {
+	if abs, err := filepath.Abs(repo); err == nil {
+		repo = abs
+	}
+	return filepath.Base(repo)
+}
@@ -0,0 +1,83 @@
+package gitlog
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newRepo creates a repository with one empty commit per entry, each
+// authored by email at the given RFC 3339 time
+func newRepo(t *testing.T, userEmail string, commits ...[3]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := filepath.Join(t.TempDir(), "backend-api")
+	run := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "GIT_CONFIG_GLOBAL="+os.DevNull)
+		cmd.Env = append(cmd.Env, env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	if err := os.MkdirAll(repo, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	run(nil, "init", "-q")
+	run(nil, "config", "user.name", "Intern")
+	run(nil, "config", "user.email", userEmail)
+	for _, c := range commits {
+		email, at, subject := c[0], c[1], c[2]
+		run([]string{
+			"GIT_AUTHOR_EMAIL=" + email, "GIT_AUTHOR_DATE=" + at,
+			"GIT_COMMITTER_EMAIL=" + email, "GIT_COMMITTER_DATE=" + at,
+		}, "commit", "-q", "--allow-empty", "-m", subject)
+	}
+	return repo
+}
+
+func subjects(commits []Commit) []string {
+	var out []string
+	for _, c := range commits {
+		out = append(out, c.Subject)
+	}
+	return out
+}
+
+func TestCommitsMatchesTheAuthorExactly(t *testing.T) {
+	repo := newRepo(t, "me+tag@example.com",
+		[3]string{"me+tag@example.com", "2026-10-19T17:00:00+07:00", "Yesterday"},
+		[3]string{"me+tag@example.com", "2026-10-20T09:00:00+07:00", "Add importer"},
+		[3]string{"meetag@example.com", "2026-10-20T09:30:00+07:00", "Regex lookalike"},
+		[3]string{"colleague@example.com", "2026-10-20T10:00:00+07:00", "Someone else"},
+		[3]string{"Me+Tag@Example.com", "2026-10-20T11:00:00+07:00", "Fix importer"},
+		[3]string{"xme+tag@example.com", "2026-10-20T12:00:00+07:00", "Substring"},
+	)
+	wib := time.FixedZone("WIB", 7*60*60)
+	date := time.Date(2026, 10, 20, 0, 0, 0, 0, wib)
+	want := []string{"Add importer", "Fix importer"}
+
+	for _, author := range []string{"me+tag@example.com", ""} {
+		commits, err := Commits(context.Background(), []string{repo}, author, date)
+		if err != nil {
+			t.Fatalf("Commits(author %q): %v", author, err)
+		}
+		if got := subjects(commits); !reflect.DeepEqual(got, want) {
+			t.Errorf("Commits(author %q) = %q, want %q", author, got, want)
+		}
+		for _, c := range commits {
+			if c.Repo != "backend-api" {
+				t.Errorf("Repo = %q, want backend-api", c.Repo)
+			}
+		}
+	}
+}
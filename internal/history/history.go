@@ -0,0 +1,178 @@
+// Package history keeps a local JSONL record of every attendance run.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Trigger says what started a run
+type Trigger string
+
+const (
+	TriggerStartup  Trigger = "startup"
+	TriggerCron     Trigger = "cron"
+	TriggerRetry    Trigger = "retry"
+	TriggerWake     Trigger = "wake"
+	TriggerManual   Trigger = "manual"
+	TriggerBackfill Trigger = "backfill"
+)
+
+// Outcome is how a run ended
+type Outcome string
+
+const (
+	OutcomeSubmitted       Outcome = "submitted"
+	OutcomeAlreadyAttended Outcome = "already_attended"
+	OutcomeSkipped         Outcome = "skipped"
+	OutcomeDryRun          Outcome = "dry_run"
+	OutcomeFailed          Outcome = "failed"
+)
+
+// LogText is the daily log content chosen for a run
+type LogText struct {
+	Day           string `json:"day"`
+	ActivityLog   string `json:"activity_log"`
+	LessonLearned string `json:"lesson_learned"`
+	Obstacles     string `json:"obstacles"`
+}
+
+// Entry is one attendance run
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Date    string    `json:"date"`
+	Trigger Trigger   `json:"trigger"`
+	Outcome Outcome   `json:"outcome"`
+	Status  string    `json:"status,omitempty"`
+	Log     *LogText  `json:"log,omitempty"`
+	// Variants are the raw daily log entries before template rendering
+	Variants []string `json:"variants,omitempty"`
+	Response string   `json:"response,omitempty"`
+	// Payload is the exact request body a dry run would have sent
+	Payload      json.RawMessage `json:"payload,omitempty"`
+	AttendanceID int             `json:"attendance_id,omitempty"`
+	Reason       string          `json:"reason,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+// Store appends entries to a JSONL file, one JSON object per line
+type Store struct {
+	mu       sync.Mutex
+	filepath string
+}
+
+// NewStore creates a store backed by the given file. The file is created on first write
+func NewStore(filepath string) *Store {
+	return &Store{filepath: filepath}
+}
+
+// Append writes an entry to the end of the file
+func (s *Store) Append(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.filepath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Query filters entries. Zero values match everything
+type Query struct {
+	// From and To bound the attendance date (YYYY-MM-DD), inclusive
+	From string
+	To   string
+	// Outcomes keeps only entries with one of these outcomes
+	Outcomes []Outcome
+	// Limit keeps only the most recent N matches
+	Limit int
+}
+
+func (q Query) matches(e Entry) bool {
+	if q.From != "" && e.Date < q.From {
+		return false
+	}
+	if q.To != "" && e.Date > q.To {
+		return false
+	}
+	if len(q.Outcomes) == 0 {
+		return true
+	}
+	for _, outcome := range q.Outcomes {
+		if e.Outcome == outcome {
+			return true
+		}
+	}
+	return false
+}
+
+// Query returns matching entries, oldest first
+func (s *Store) Query(q Query) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.filepath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.filepath, line, err)
+		}
+		if q.matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if q.Limit > 0 && len(entries) > q.Limit {
+		entries = entries[len(entries)-q.Limit:]
+	}
+	return entries, nil
+}
+
+// Last returns the most recent entry, or nil if there is none
+func (s *Store) Last() (*Entry, error) {
+	entries, err := s.Query(Query{Limit: 1})
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// LastSuccess returns the most recent submitted entry, or nil if there is none
+func (s *Store) LastSuccess() (*Entry, error) {
+	entries, err := s.Query(Query{Outcomes: []Outcome{OutcomeSubmitted}, Limit: 1})
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
@@ -0,0 +1,145 @@
+// Package holiday knows which dates are Indonesian public holidays or
+// collective leave (cuti bersama), so no attendance is submitted on them.
+package holiday
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dateLayout is the YYYY-MM-DD layout used for holiday dates
+const dateLayout = "2006-01-02"
+
+// Kind distinguishes national holidays from collective leave
+type Kind string
+
+const (
+	KindNational        Kind = "national"
+	KindCollectiveLeave Kind = "collective_leave"
+)
+
+// Holiday is a single day off
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+	Kind Kind   `json:"type"`
+}
+
+// String describes the holiday for log output
+func (h Holiday) String() string {
+	if h.Kind == KindCollectiveLeave {
+		return fmt.Sprintf("%s (cuti bersama)", h.Name)
+	}
+	return fmt.Sprintf("%s (libur nasional)", h.Name)
+}
+
+// Calendar is a set of holidays indexed by date
+type Calendar struct {
+	byDate map[string]Holiday
+	years  map[string]bool
+}
+
+type calendarFile struct {
+	Holidays []Holiday `json:"holidays"`
+}
+
+//go:embed holidays_id.json
+var bundledData []byte
+
+// Bundled returns the calendar shipped with the binary
+func Bundled() (*Calendar, error) {
+	cal, err := parseJSON(bundledData)
+	if err != nil {
+		return nil, fmt.Errorf("bundled holidays: %w", err)
+	}
+	return cal, nil
+}
+
+// Load reads a user-supplied calendar. Files ending in .ics are parsed as
+// iCalendar, anything else as the JSON format of the bundled calendar
+func Load(path string) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cal *Calendar
+	if strings.EqualFold(filepath.Ext(path), ".ics") {
+		cal, err = parseICS(data)
+	} else {
+		cal, err = parseJSON(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cal, nil
+}
+
+func parseJSON(data []byte) (*Calendar, error) {
+	var file calendarFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid holiday JSON: %w", err)
+	}
+
+	cal := newCalendar()
+	for _, h := range file.Holidays {
+		if _, err := time.Parse(dateLayout, h.Date); err != nil {
+			return nil, fmt.Errorf("invalid holiday date %q", h.Date)
+		}
+		if h.Kind == "" {
+			h.Kind = KindNational
+		}
+		if h.Kind != KindNational && h.Kind != KindCollectiveLeave {
+			return nil, fmt.Errorf("%s: unknown holiday type %q", h.Date, h.Kind)
+		}
+		cal.add(h)
+	}
+	return cal, nil
+}
+
+func newCalendar() *Calendar {
+	return &Calendar{byDate: make(map[string]Holiday), years: make(map[string]bool)}
+}
+
+// add stores a holiday; a national holiday wins over collective leave on the same day
+func (c *Calendar) add(h Holiday) {
+	c.years[h.Date[:4]] = true
+	if existing, ok := c.byDate[h.Date]; ok && existing.Kind == KindNational {
+		return
+	}
+	c.byDate[h.Date] = h
+}
+
+// Lookup returns the holiday on date (YYYY-MM-DD), if any
+func (c *Calendar) Lookup(date string) (Holiday, bool) {
+	h, ok := c.byDate[date]
+	return h, ok
+}
+
+// Covers reports whether the calendar lists any holiday in the year of
+// date (YYYY-MM-DD). A year without any is most likely missing, and every
+// day of it would look like a working day
+func (c *Calendar) Covers(date string) bool {
+	return len(date) >= 4 && c.years[date[:4]]
+}
+
+// Len returns the number of holiday dates in the calendar
+func (c *Calendar) Len() int {
+	return len(c.byDate)
+}
+
+// Dates returns all holiday dates in order
+func (c *Calendar) Dates() []string {
+	dates := make([]string, 0, len(c.byDate))
+	for date := range c.byDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates
+}
@@ -0,0 +1,82 @@
+package holiday
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadJSON(t *testing.T) {
+	cal, err := writeCalendar(t, "holidays.json", `{"holidays": [
+		{"date": "2026-01-01", "name": "Tahun Baru"},
+		{"date": "2026-02-16", "name": "Cuti Bersama Imlek", "type": "collective_leave"},
+		{"date": "2026-02-16", "name": "Imlek", "type": "national"},
+		{"date": "2026-02-16", "name": "Cuti lagi", "type": "collective_leave"}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h, ok := cal.Lookup("2026-01-01"); !ok || h.Kind != KindNational || h.String() != "Tahun Baru (libur nasional)" {
+		t.Errorf("Lookup(2026-01-01) = %+v, %v; want a national holiday by default", h, ok)
+	}
+	// A national holiday wins over collective leave, in either order
+	if h, ok := cal.Lookup("2026-02-16"); !ok || h.Name != "Imlek" {
+		t.Errorf("Lookup(2026-02-16) = %+v, %v; want Imlek", h, ok)
+	}
+	if cal.Len() != 2 {
+		t.Errorf("Len = %d, want 2", cal.Len())
+	}
+}
+
+func TestLoadJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"malformed JSON", `{"holidays": [`, "invalid holiday JSON"},
+		{"bad date", `{"holidays": [{"date": "2026-13-01", "name": "x"}]}`, `invalid holiday date "2026-13-01"`},
+		{"unknown type", `{"holidays": [{"date": "2026-01-01", "name": "x", "type": "regional"}]}`, `2026-01-01: unknown holiday type "regional"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := writeCalendar(t, "holidays.json", tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupOutsideTheCoveredRange(t *testing.T) {
+	cal, err := writeCalendar(t, "holidays.json", `{"holidays": [{"date": "2026-08-17", "name": "Hari Kemerdekaan"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, date := range []string{"2025-08-17", "2027-08-17", "2026-08-18"} {
+		if h, ok := cal.Lookup(date); ok {
+			t.Errorf("Lookup(%s) = %+v, want no holiday", date, h)
+		}
+	}
+	// Only a date in a year without any holiday is reported as uncovered
+	for date, want := range map[string]bool{"2026-01-02": true, "2025-08-17": false, "2027-08-17": false, "": false} {
+		if got := cal.Covers(date); got != want {
+			t.Errorf("Covers(%q) = %v, want %v", date, got, want)
+		}
+	}
+}
+
+func TestBundled(t *testing.T) {
+	cal, err := Bundled()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h, ok := cal.Lookup("2026-08-17"); !ok || h.Kind != KindNational {
+		t.Errorf("Lookup(2026-08-17) = %+v, %v; want Independence Day", h, ok)
+	}
+	if !cal.Covers("2026-06-01") {
+		t.Error("the bundled calendar does not cover 2026")
+	}
+}
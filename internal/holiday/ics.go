@@ -0,0 +1,132 @@
+package holiday
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// icsEvent holds the VEVENT properties we care about
+type icsEvent struct {
+	start      string
+	end        string
+	summary    string
+	categories string
+}
+
+// parseICS reads all-day VEVENTs from an iCalendar file. Multi-day events
+// expand to one holiday per day; DTEND is exclusive as per RFC 5545.
+// Events whose summary or categories mention "cuti" count as collective leave
+func parseICS(data []byte) (*Calendar, error) {
+	cal := newCalendar()
+
+	var event *icsEvent
+	for _, line := range unfoldICS(data) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Drop parameters such as DTSTART;VALUE=DATE
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+
+		switch name {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				event = &icsEvent{}
+			}
+		case "END":
+			if strings.EqualFold(value, "VEVENT") && event != nil {
+				if err := addICSEvent(cal, event); err != nil {
+					return nil, err
+				}
+				event = nil
+			}
+		case "DTSTART":
+			if event != nil {
+				event.start = value
+			}
+		case "DTEND":
+			if event != nil {
+				event.end = value
+			}
+		case "SUMMARY":
+			if event != nil {
+				event.summary = unescapeICS(value)
+			}
+		case "CATEGORIES":
+			if event != nil {
+				event.categories = unescapeICS(value)
+			}
+		}
+	}
+
+	return cal, nil
+}
+
+func addICSEvent(cal *Calendar, event *icsEvent) error {
+	start, err := parseICSDate(event.start)
+	if err != nil {
+		return fmt.Errorf("event %q: %w", event.summary, err)
+	}
+
+	end := start.AddDate(0, 0, 1)
+	if event.end != "" {
+		end, err = parseICSDate(event.end)
+		if err != nil {
+			return fmt.Errorf("event %q: %w", event.summary, err)
+		}
+	}
+
+	kind := KindNational
+	text := strings.ToLower(event.summary + " " + event.categories)
+	if strings.Contains(text, "cuti") || strings.Contains(text, "collective") {
+		kind = KindCollectiveLeave
+	}
+
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		cal.add(Holiday{
+			Date: day.Format(dateLayout),
+			Name: event.summary,
+			Kind: kind,
+		})
+	}
+	// Zero-length events still mark their start day
+	if !start.Before(end) {
+		cal.add(Holiday{Date: start.Format(dateLayout), Name: event.summary, Kind: kind})
+	}
+	return nil
+}
+
+// parseICSDate accepts DATE (20260101) and DATE-TIME (20260101T000000Z) values
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	t, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return t, nil
+}
+
+// unfoldICS joins continuation lines (starting with a space or tab)
+func unfoldICS(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func unescapeICS(value string) string {
+	r := strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`)
+	return strings.TrimSpace(r.Replace(value))
+}
@@ -0,0 +1,114 @@
+package holiday
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testICS uses CRLF line endings and folds lines as calendar exports do
+const testICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20260817\r\n" +
+	"DTEND;VALUE=DATE:20260818\r\n" +
+	"SUMMARY:Hari Kemerdekaan Republik \r\n" +
+	" Indonesia\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20260320\r\n" +
+	"DTEND;VALUE=DATE:20260325\r\n" +
+	"SUMMARY:Cuti Bersama Idul Fitri\\, 1447 H\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20260321\r\n" +
+	"SUMMARY:Hari Raya Idul Fitri\r\n" +
+	"CATEGORIES:Public holiday\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20261225T000000Z\r\n" +
+	"SUMMARY:Hari Raya Natal\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20261224\r\n" +
+	"DTEND;VALUE=DATE:20261225\r\n" +
+	"SUMMARY:Pengganti\r\n" +
+	"CATEGORIES:Collective leave\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// writeCalendar writes data to name in a temp dir and loads it
+func writeCalendar(t *testing.T, name, data string) (*Calendar, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Load(path)
+}
+
+func TestLoadICS(t *testing.T) {
+	cal, err := writeCalendar(t, "holidays.ICS", testICS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		date string
+		want Holiday
+	}{
+		// Folded SUMMARY, one-day event with an exclusive DTEND
+		{"2026-08-17", Holiday{"2026-08-17", "Hari Kemerdekaan Republik Indonesia", KindNational}},
+		// Multi-day event; the national holiday on the 21st wins
+		{"2026-03-20", Holiday{"2026-03-20", "Cuti Bersama Idul Fitri, 1447 H", KindCollectiveLeave}},
+		{"2026-03-21", Holiday{"2026-03-21", "Hari Raya Idul Fitri", KindNational}},
+		{"2026-03-24", Holiday{"2026-03-24", "Cuti Bersama Idul Fitri, 1447 H", KindCollectiveLeave}},
+		// DATE-TIME without DTEND
+		{"2026-12-25", Holiday{"2026-12-25", "Hari Raya Natal", KindNational}},
+		// Collective leave from CATEGORIES
+		{"2026-12-24", Holiday{"2026-12-24", "Pengganti", KindCollectiveLeave}},
+	}
+	for _, tt := range tests {
+		got, ok := cal.Lookup(tt.date)
+		if !ok || got != tt.want {
+			t.Errorf("Lookup(%s) = %+v, %v; want %+v", tt.date, got, ok, tt.want)
+		}
+	}
+
+	// DTEND is exclusive
+	for _, date := range []string{"2026-08-18", "2026-03-25", "2026-12-26"} {
+		if h, ok := cal.Lookup(date); ok {
+			t.Errorf("Lookup(%s) = %+v, want no holiday", date, h)
+		}
+	}
+
+	want := []string{"2026-03-20", "2026-03-21", "2026-03-22", "2026-03-23", "2026-03-24", "2026-08-17", "2026-12-24", "2026-12-25"}
+	if got := cal.Dates(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Dates = %v, want %v", got, want)
+	}
+}
+
+func TestLoadICSErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		want  string
+	}{
+		{"short start", "DTSTART;VALUE=DATE:2026\r\nSUMMARY:Pendek\r\n", `event "Pendek": invalid date "2026"`},
+		{"bad start", "DTSTART;VALUE=DATE:2026AB01\r\nSUMMARY:Salah\r\n", `event "Salah": invalid date "2026AB01"`},
+		{"bad end", "DTSTART;VALUE=DATE:20260101\r\nDTEND;VALUE=DATE:x\r\nSUMMARY:Akhir\r\n", `event "Akhir": invalid date "x"`},
+		{"missing start", "SUMMARY:Tanpa tanggal\r\n", `event "Tanpa tanggal": invalid date ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n" + tt.event + "END:VEVENT\r\nEND:VCALENDAR\r\n"
+			_, err := writeCalendar(t, "holidays.ics", data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+// Package metrics keeps the daemon's counters, gauges and histograms and
+// renders them in the Prometheus text exposition format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Runs counts attendance runs by how they ended
+var Runs = NewCounter("maganghub_runs_total", "Attendance runs by outcome.", "outcome")
+
+// Logins counts browser logins by result ("success" or "failure")
+var Logins = NewCounter("maganghub_logins_total", "Playwright logins by result.", "result")
+
+// CookieCacheHits counts runs that reused valid cached cookies
+var CookieCacheHits = NewCounter("maganghub_cookie_cache_hits_total", "Runs that reused valid cached cookies.")
+
+// CookieCacheMisses counts runs that had to log in because there were no
+// cached cookies or they were rejected
+var CookieCacheMisses = NewCounter("maganghub_cookie_cache_misses_total", "Runs without usable cached cookies.")
+
+// APIRequests counts Monev API requests by endpoint and HTTP status code,
+// with code "error" when no response arrived
+var APIRequests = NewCounter("maganghub_api_requests_total", "Monev API requests by endpoint and status code.", "endpoint", "code")
+
+// APILatency observes how long Monev API requests take
+var APILatency = NewHistogram("maganghub_api_request_duration_seconds", "Monev API request latency.",
+	[]float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}, "endpoint")
+
+// LoginDuration observes how long a Playwright login takes
+var LoginDuration = NewHistogram("maganghub_login_duration_seconds", "Playwright login duration.",
+	[]float64{1, 2.5, 5, 10, 15, 20, 30, 45, 60, 120})
+
+// LastSuccess is when attendance was last submitted
+var LastSuccess = NewGauge("maganghub_last_success_timestamp_seconds", "Unix time of the last submitted attendance.")
+
+// LastLogin is when a browser login last succeeded
+var LastLogin = NewGauge("maganghub_last_login_success_timestamp_seconds", "Unix time of the last successful Playwright login.")
+
+// registry holds every metric in the order it was created
+var registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+func register(m metric) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.metrics = append(registry.metrics, m)
+}
+
+// family is the name, help and label names shared by a metric's series
+type family struct {
+	name   string
+	help   string
+	labels []string
+}
+
+// key joins label values into a map key; values are checked against the
+// label names so a mistake panics at the call site, like the Prometheus client
+func (f *family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelPairs renders {a="x",b="y"} plus any extra pair, or "" without labels
+func (f *family) labelPairs(key string, extra ...string) string {
+	var pairs []string
+	if len(f.labels) > 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			pairs = append(pairs, f.labels[i]+`="`+escapeLabel(value)+`"`)
+		}
+	}
+	if len(extra) == 2 {
+		pairs = append(pairs, extra[0]+`="`+extra[1]+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (f *family) header(w *bufio.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, kind)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Counter is a monotonically increasing value per label set
+type Counter struct {
+	family
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounter creates and registers a counter with the given label names
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{family: family{name, help, labels}, values: make(map[string]float64)}
+	register(c)
+	return c
+}
+
+// Inc adds one to the series with these label values
+func (c *Counter) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+// Add adds v, which must not be negative, to the series with these label values
+func (c *Counter) Add(v float64, labels ...string) {
+	key := c.key(labels)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] += v
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.header(w, "counter")
+	// A counter without labels is always exposed, starting at zero
+	if len(c.labels) == 0 && len(c.values) == 0 {
+		fmt.Fprintf(w, "%s 0\n", c.name)
+	}
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelPairs(key), formatFloat(c.values[key]))
+	}
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	family
+	mu    sync.Mutex
+	value float64
+}
+
+// NewGauge creates and registers a gauge
+func NewGauge(name, help string) *Gauge {
+	g := &Gauge{family: family{name: name, help: help}}
+	register(g)
+	return g
+}
+
+// Set sets the gauge to v
+func (g *Gauge) Set(v float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.value = v
+}
+
+// SetTime sets the gauge to t as Unix seconds
+func (g *Gauge) SetTime(t time.Time) {
+	g.Set(float64(t.UnixNano()) / 1e9)
+}
+
+func (g *Gauge) write(w *bufio.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.header(w, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.value))
+}
+
+// Histogram counts observations into cumulative buckets per label set
+type Histogram struct {
+	family
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram creates and registers a histogram with the given upper
+// bucket bounds; the +Inf bucket is added automatically
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		family:  family{name, help, labels},
+		buckets: slices.Sorted(slices.Values(buckets)),
+		series:  make(map[string]*histogramSeries),
+	}
+	register(h)
+	return h
+}
+
+// Observe records v in the series with these label values
+func (h *Histogram) Observe(v float64, labels ...string) {
+	key := h.key(labels)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.series[key]
+	if s == nil {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i, _ := slices.BinarySearch(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+// ObserveSince records the time elapsed since start in seconds
+func (h *Histogram) ObserveSince(start time.Time, labels ...string) {
+	h.Observe(time.Since(start).Seconds(), labels...)
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.header(w, "histogram")
+	if len(h.labels) == 0 && len(h.series) == 0 {
+		h.series[""] = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+	}
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(key, "le", formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(key, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelPairs(key), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(key), s.count)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// WriteText writes every registered metric in the Prometheus text format
+func WriteText(w io.Writer) error {
+	registry.mu.Lock()
+	metrics := slices.Clone(registry.metrics)
+	registry.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler serves the metrics for a Prometheus scrape
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := WriteText(w); err != nil {
+			// The status line is already sent, so the scraper only sees a cut-off body
+			log.Printf("Warning: Failed to write metrics: %v", err)
+		}
+	})
+}
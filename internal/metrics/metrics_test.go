@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exposition renders every registered metric
+func exposition(t *testing.T) string {
+	t.Helper()
+	var b strings.Builder
+	if err := WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+// assertBlock checks that the exposition contains want as consecutive lines
+func assertBlock(t *testing.T, text, want string) {
+	t.Helper()
+	if !strings.Contains(text, want) {
+		t.Errorf("exposition is missing:\n%s\ngot:\n%s", want, text)
+	}
+}
+
+func TestCounterExposition(t *testing.T) {
+	c := NewCounter("test_events_total", "Events by kind.", "kind", "source")
+	c.Inc("b", "api")
+	c.Add(2.5, "a", `quote" back\slash`+"\nline")
+	c.Inc("b", "api")
+
+	assertBlock(t, exposition(t), `# HELP test_events_total Events by kind.
+# TYPE test_events_total counter
+test_events_total{kind="a",source="quote\" back\\slash\nline"} 2.5
+test_events_total{kind="b",source="api"} 2
+`)
+}
+
+func TestCounterWithoutLabelsStartsAtZero(t *testing.T) {
+	NewCounter("test_unlabeled_total", "Unlabeled.")
+	NewCounter("test_labeled_total", "Labeled.", "kind")
+
+	text := exposition(t)
+	assertBlock(t, text, "# TYPE test_unlabeled_total counter\ntest_unlabeled_total 0\n")
+	// A labeled counter has no series until one is used
+	assertBlock(t, text, "# TYPE test_labeled_total counter\n")
+	if strings.Contains(text, "\ntest_labeled_total") {
+		t.Errorf("unused labeled counter has a series:\n%s", text)
+	}
+}
+
+func TestCounterPanicsOnWrongLabelCount(t *testing.T) {
+	c := NewCounter("test_panics_total", "Panics.", "kind")
+	defer func() {
+		if recover() == nil {
+			t.Error("Inc with too many label values did not panic")
+		}
+	}()
+	c.Inc("a", "b")
+}
+
+func TestGaugeExposition(t *testing.T) {
+	g := NewGauge("test_last_seconds", "Last time.")
+	g.SetTime(time.Unix(1700000000, 500000000))
+
+	assertBlock(t, exposition(t), `# HELP test_last_seconds Last time.
+# TYPE test_last_seconds gauge
+test_last_seconds 1.7000000005e+09
+`)
+}
+
+func TestHistogramExposition(t *testing.T) {
+	h := NewHistogram("test_latency_seconds", "Latency.", []float64{1, 0.5, 2}, "endpoint")
+	for _, v := range []float64{0.2, 0.5, 1.5, 3} {
+		h.Observe(v, "me")
+	}
+
+	assertBlock(t, exposition(t), `# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{endpoint="me",le="0.5"} 2
+test_latency_seconds_bucket{endpoint="me",le="1"} 2
+test_latency_seconds_bucket{endpoint="me",le="2"} 3
+test_latency_seconds_bucket{endpoint="me",le="+Inf"} 4
+test_latency_seconds_sum{endpoint="me"} 5.2
+test_latency_seconds_count{endpoint="me"} 4
+`)
+}
+
+func TestHistogramWithoutLabelsStartsEmpty(t *testing.T) {
+	NewHistogram("test_duration_seconds", "Duration.", []float64{1})
+
+	assertBlock(t, exposition(t), `# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="1"} 0
+test_duration_seconds_bucket{le="+Inf"} 0
+test_duration_seconds_sum 0
+test_duration_seconds_count 0
+`)
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if got := rec.Header().Get("content-type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("content-type = %q", got)
+	}
+	assertBlock(t, rec.Body.String(), "# TYPE maganghub_runs_total counter\n")
+}
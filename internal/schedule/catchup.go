@@ -0,0 +1,149 @@
+package schedule
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+	"maganghub-autopresence/internal/history"
+)
+
+// RunState persists the date of the last successful run so a slot missed
+// while the daemon was down can be detected after a restart
+type RunState struct {
+	mu          sync.RWMutex
+	filepath    string
+	lastSuccess string
+}
+
+type runStateFile struct {
+	LastSuccess string `json:"last_success"`
+}
+
+// NewRunState creates a run state backed by the given file
+func NewRunState(filepath string) *RunState {
+	rs := &RunState{filepath: filepath}
+	rs.load() // Try to load existing state
+	return rs
+}
+
+// LastSuccess returns the date (YYYY-MM-DD) of the last successful run
+func (rs *RunState) LastSuccess() string {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.lastSuccess
+}
+
+// MarkSuccess records date as successfully handled
+func (rs *RunState) MarkSuccess(date string) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.lastSuccess = date
+
+	data, err := json.MarshalIndent(runStateFile{LastSuccess: date}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(rs.filepath, data, 0600)
+}
+
+// load loads state from file
+func (rs *RunState) load() {
+	data, err := os.ReadFile(rs.filepath)
+	if err != nil {
+		return
+	}
+
+	var state runStateFile
+	if err := json.Unmarshal(data, &state); err != nil {
+		return
+	}
+
+	rs.lastSuccess = state.LastSuccess
+}
+
+// wakeCheckInterval is how often the wall clock is compared to the monotonic clock
+const wakeCheckInterval = time.Minute
+
+// EnableCatchUp makes the scheduler record successful runs in state and run
+// a missed slot for today when CatchUp is called, as long as no more than
+// grace has passed since the slot. Call it before Start
+func (s *Scheduler) EnableCatchUp(state *RunState, grace time.Duration) {
+	s.state = state
+	s.grace = grace
+}
+
+// CatchUp runs today's slot if it was missed while the daemon was down or
+// the machine was asleep, judged at now. Past the grace deadline it only
+// logs an alert
+func (s *Scheduler) CatchUp(now time.Time, trigger history.Trigger) {
+	if s.state == nil || s.spec == nil {
+		return
+	}
+
+	now = now.In(api.Location)
+	today := now.Format(dateLayout)
+	if s.state.LastSuccess() == today {
+		return
+	}
+
+	slot, ok := s.todaySlot(now)
+	if !ok || slot.After(now) {
+		return
+	}
+
+	slotTime := slot.Format("15:04:05")
+	if late := now.Sub(slot); late > s.grace {
+		s.mu.Lock()
+		alerted := s.alertedDate == today
+		s.alertedDate = today
+		s.mu.Unlock()
+
+		if !alerted {
+			log.Printf("🚨 ALERT: today's run at %s was missed and the %s grace period has passed (%s late), run the \"run\" command to submit", slotTime, s.grace, late.Round(time.Minute))
+		}
+		return
+	}
+
+	log.Printf("⏰ Today's run at %s was missed, catching up now", slotTime)
+	s.runJob("Catch-up", trigger, s.job)
+}
+
+// todaySlot returns the first scheduled time on now's calendar day, if any.
+// now must be in WIB
+func (s *Scheduler) todaySlot(now time.Time) (time.Time, bool) {
+	day := startOfDay(now)
+	slot := s.spec.Next(day.Add(-time.Second))
+	if slot.IsZero() || !slot.Before(day.AddDate(0, 0, 1)) {
+		return time.Time{}, false
+	}
+	return slot, true
+}
+
+// watchWake calls CatchUp when the wall clock jumps ahead of the monotonic
+// clock, which happens when the machine resumes from sleep
+func (s *Scheduler) watchWake() {
+	ticker := time.NewTicker(wakeCheckInterval)
+	defer ticker.Stop()
+
+	prev := time.Now()
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			wall := now.Round(0).Sub(prev.Round(0))
+			mono := now.Sub(prev)
+			prev = now
+			if wall-mono > wakeCheckInterval {
+				log.Printf("💤 Woke up after %s asleep, checking for missed runs", (wall - mono).Round(time.Minute))
+				s.CatchUp(now, history.TriggerWake)
+			}
+		}
+	}
+}
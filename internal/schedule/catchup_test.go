@@ -0,0 +1,139 @@
+package schedule
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+	"maganghub-autopresence/internal/history"
+)
+
+// newCatchUpScheduler returns a scheduler set up as Start would, without
+// starting cron, and a pointer to the triggers its job ran with
+func newCatchUpScheduler(t *testing.T, cronExpression string, window *Window, lastSuccess string) (*Scheduler, *[]history.Trigger) {
+	t.Helper()
+	state := NewRunState(filepath.Join(t.TempDir(), "run_state.json"))
+	if lastSuccess != "" {
+		if err := state.MarkSuccess(lastSuccess); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewScheduler(cronExpression, window)
+	s.EnableCatchUp(state, 4*time.Hour)
+	spec, err := s.parseSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.spec = spec
+
+	var runs []history.Trigger
+	s.job = func(trigger history.Trigger) error {
+		runs = append(runs, trigger)
+		return nil
+	}
+	return s, &runs
+}
+
+func TestCatchUp(t *testing.T) {
+	// 2026-10-19 is a Monday, 2026-10-24 a Saturday
+	at := func(date, clock string) time.Time {
+		t, err := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, api.Location)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		lastSuccess string
+		wantRun     bool
+	}{
+		{"before today's slot", at("2026-10-19", "07:59"), "2026-10-16", false},
+		{"just after the slot", at("2026-10-19", "08:01"), "2026-10-16", true},
+		{"inside the grace period", at("2026-10-19", "11:30"), "2026-10-16", true},
+		{"at the end of the grace period", at("2026-10-19", "12:00"), "2026-10-16", true},
+		{"past the grace period", at("2026-10-19", "12:01"), "2026-10-16", false},
+		{"already ran today", at("2026-10-19", "09:00"), "2026-10-19", false},
+		{"never ran", at("2026-10-19", "09:00"), "", true},
+		{"non-scheduled day", at("2026-10-24", "09:00"), "2026-10-23", false},
+		{"time given in UTC", time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC), "2026-10-16", true},
+		{"UTC date is still yesterday", time.Date(2026, 10, 18, 17, 30, 0, 0, time.UTC), "2026-10-16", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, runs := newCatchUpScheduler(t, "0 8 * * 1-5", nil, tt.lastSuccess)
+			s.CatchUp(tt.now, history.TriggerStartup)
+
+			if got := len(*runs) == 1; got != tt.wantRun {
+				t.Fatalf("ran %d times, want run = %v", len(*runs), tt.wantRun)
+			}
+			if tt.wantRun && (*runs)[0] != history.TriggerStartup {
+				t.Errorf("trigger = %q, want %q", (*runs)[0], history.TriggerStartup)
+			}
+		})
+	}
+}
+
+func TestCatchUpAlertsOncePerDay(t *testing.T) {
+	s, runs := newCatchUpScheduler(t, "0 8 * * 1-5", nil, "2026-10-16")
+	late := time.Date(2026, 10, 19, 13, 0, 0, 0, api.Location)
+
+	s.CatchUp(late, history.TriggerStartup)
+	if s.alertedDate != "2026-10-19" {
+		t.Fatalf("alertedDate = %q, want 2026-10-19", s.alertedDate)
+	}
+	s.CatchUp(late.Add(time.Hour), history.TriggerWake)
+	if len(*runs) != 0 {
+		t.Errorf("ran %d times past the grace period", len(*runs))
+	}
+}
+
+func TestCatchUpWithoutState(t *testing.T) {
+	s, runs := newCatchUpScheduler(t, "0 8 * * 1-5", nil, "")
+	s.state = nil
+	s.CatchUp(time.Date(2026, 10, 19, 9, 0, 0, 0, api.Location), history.TriggerStartup)
+	if len(*runs) != 0 {
+		t.Errorf("ran %d times with catch-up disabled", len(*runs))
+	}
+}
+
+func TestTodaySlot(t *testing.T) {
+	w, err := ParseWindow("07:45-08:30", "seed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, api.Location)
+
+	plain, _ := newCatchUpScheduler(t, "30 9 * * 1-5", nil, "")
+	if slot, ok := plain.todaySlot(monday.Add(15 * time.Hour)); !ok || !slot.Equal(monday.Add(9*time.Hour+30*time.Minute)) {
+		t.Errorf("todaySlot = %s, %v; want 09:30", slot, ok)
+	}
+	if _, ok := plain.todaySlot(monday.AddDate(0, 0, 5).Add(15 * time.Hour)); ok {
+		t.Error("todaySlot found a slot on Saturday")
+	}
+
+	// With a window the slot is the day's drawn time, also when asked
+	// before it
+	windowed, _ := newCatchUpScheduler(t, "0 8 * * 1-5", w, "")
+	for _, now := range []time.Time{monday, monday.Add(23 * time.Hour)} {
+		if slot, ok := windowed.todaySlot(now); !ok || !slot.Equal(w.RunTime(monday)) {
+			t.Errorf("todaySlot(%s) = %s, %v; want %s", now.Format("15:04"), slot, ok, w.RunTime(monday))
+		}
+	}
+
+	// A catch-up run uses the drawn time too
+	s, runs := newCatchUpScheduler(t, "0 8 * * 1-5", w, "2026-10-16")
+	s.CatchUp(w.RunTime(monday).Add(-time.Minute), history.TriggerStartup)
+	if len(*runs) != 0 {
+		t.Error("caught up before the drawn time")
+	}
+	s.CatchUp(w.RunTime(monday).Add(time.Minute), history.TriggerStartup)
+	if len(*runs) != 1 {
+		t.Error("did not catch up after the drawn time")
+	}
+}
@@ -0,0 +1,62 @@
+package schedule
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"maganghub-autopresence/internal/gitlog"
+)
+
+// DefaultCommitTemplate summarises the day's commits into an activity log
+const DefaultCommitTemplate = `Pada hari {{.Weekday}} ini saya mengerjakan {{len .Commits}} perubahan pada repositori {{join .Repos ", "}}, yaitu: {{range $i, $c := .Commits}}{{if $i}}; {{end}}{{$c.Subject}}{{end}}.`
+
+// CommitData is available to the commit summary template. It embeds the
+// daily log placeholders, e.g. "{{.JobRole}}: {{range .Commits}}..."
+type CommitData struct {
+	TemplateData
+	// Commits are the day's commits, oldest first
+	Commits []gitlog.Commit
+	// Repos are the distinct repository names in commit order
+	Repos []string
+}
+
+// LoadCommitTemplate reads a commit summary template from filepath, or
+// returns DefaultCommitTemplate when filepath is empty
+func LoadCommitTemplate(filepath string) (string, error) {
+	if filepath == "" {
+		return DefaultCommitTemplate, nil
+	}
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SummarizeCommits renders commits into an activity log paragraph
+func SummarizeCommits(text string, commits []gitlog.Commit, data TemplateData) (string, error) {
+	tmpl, err := template.New("commits").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"join": strings.Join}).
+		Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("template commit tidak valid: %w", err)
+	}
+
+	commitData := CommitData{TemplateData: data, Commits: commits}
+	seen := make(map[string]bool)
+	for _, c := range commits {
+		if !seen[c.Repo] {
+			seen[c.Repo] = true
+			commitData.Repos = append(commitData.Repos, c.Repo)
+		}
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, commitData); err != nil {
+		return "", fmt.Errorf("template commit gagal dirender: %w", err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
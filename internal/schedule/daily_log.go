@@ -5,11 +5,17 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"time"
+
+	"maganghub-autopresence/internal/api"
 )
 
-// DailyLog represents a daily log entry for attendance
+// DailyLog represents a daily log entry for attendance. Day is a weekday
+// name, "Fallback", an exact date ("2026-10-20") or an inclusive date range
+// ("2026-11-01..2026-11-07")
 type DailyLog struct {
 	Day           string   `json:"day"`
 	ActivityLog   []string `json:"activity_log"`
@@ -23,6 +29,9 @@ type ResolvedDailyLog struct {
 	ActivityLog   string
 	LessonLearned string
 	Obstacles     string
+	// Variants are the raw entries the fields were rendered from,
+	// used to avoid picking the same variant again too soon
+	Variants []string
 }
 
 type dailyLogsByDay struct {
@@ -35,19 +44,21 @@ type dailyLogEntry struct {
 	Obstacles     []string `json:"obstacles"`
 }
 
-// LoadDailyLogs loads daily logs from a JSON file
-func LoadDailyLogs(filepath string) ([]DailyLog, error) {
-	data, err := os.ReadFile(filepath)
+// LoadDailyLogs loads daily logs from a JSON, YAML (.yaml, .yml) or
+// Markdown (.md) file, picked by extension
+func LoadDailyLogs(file string) ([]DailyLog, error) {
+	data, err := os.ReadFile(file)
 	if err != nil {
 		return nil, err
 	}
 
-	var byDay dailyLogsByDay
-	if err := json.Unmarshal(data, &byDay); err != nil {
-		return nil, fmt.Errorf("format daily_logs.json tidak valid: %w", err)
+	name := filepath.Base(file)
+	byDay, err := decodeDailyLogs(file, data)
+	if err != nil {
+		return nil, fmt.Errorf("format %s tidak valid: %w", name, err)
 	}
 	if len(byDay.Days) == 0 {
-		return nil, fmt.Errorf("format daily_logs.json tidak valid: field days wajib ada dan tidak boleh kosong")
+		return nil, fmt.Errorf("format %s tidak valid: field days wajib ada dan tidak boleh kosong", name)
 	}
 
 	var dayKeys []string
@@ -58,6 +69,12 @@ func LoadDailyLogs(filepath string) ([]DailyLog, error) {
 
 	logs := make([]DailyLog, 0, len(dayKeys))
 	for _, day := range dayKeys {
+		if isDateKey(day) {
+			if _, err := parseDateSpec(day); err != nil {
+				return nil, fmt.Errorf("format %s tidak valid: %w", name, err)
+			}
+		}
+
 		entry := byDay.Days[day]
 		logs = append(logs, DailyLog{
 			Day:           day,
@@ -70,85 +87,141 @@ func LoadDailyLogs(filepath string) ([]DailyLog, error) {
 	return logs, nil
 }
 
-// GetTodayLog returns a randomly selected log entry for today's day of week
-func GetTodayLog(logs []DailyLog) *ResolvedDailyLog {
-	today := time.Now().Weekday().String()
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+// DailyLogFormat names the file format LoadDailyLogs uses for file:
+// "yaml", "markdown" or "json"
+func DailyLogFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".md", ".markdown":
+		return "markdown"
+	default:
+		return "json"
+	}
+}
 
-	var candidates []DailyLog
-	var fallback []DailyLog
-	for i := range logs {
-		if logs[i].Day == today {
-			candidates = append(candidates, logs[i])
-		}
-		if logs[i].Day == "Fallback" {
-			fallback = append(fallback, logs[i])
+// decodeDailyLogs decodes any supported format into the JSON document shape
+func decodeDailyLogs(file string, data []byte) (dailyLogsByDay, error) {
+	var byDay dailyLogsByDay
+
+	switch DailyLogFormat(file) {
+	case "yaml":
+		root, err := parseYAML(data)
+		if err != nil {
+			return byDay, err
 		}
+		return decodeYAMLDoc(root)
+	case "markdown":
+		return parseMarkdownLogs(data)
+	default:
+		err := json.Unmarshal(data, &byDay)
+		return byDay, err
 	}
+}
 
-	if len(candidates) == 0 {
-		candidates = fallback
-	}
+// SelectOptions tunes how SelectLog picks a log entry and its variants
+type SelectOptions struct {
+	// Date is the submission date; it picks the entry by exact date,
+	// then date range, then weekday
+	Date time.Time
+	// Used tells when each variant was last submitted, may be nil
+	Used UsageHistory
+	// AvoidDays skips variants submitted within this many days, when possible
+	AvoidDays int
+	// User fills profile placeholders in templated variants, may be nil
+	User *api.User
+}
 
-	if len(candidates) == 0 && len(logs) > 0 {
-		candidates = logs
-	}
+// GetTodayLog returns a randomly selected log entry for today
+func GetTodayLog(logs []DailyLog) (*ResolvedDailyLog, error) {
+	return SelectLog(logs, SelectOptions{Date: time.Now()})
+}
+
+// SelectLog returns a log entry for the date. An exact date entry beats a
+// date range, a range beats the weekday and the weekday beats "Fallback";
+// among overlapping ranges the shortest wins. Each field is picked at
+// random among variants not used within AvoidDays; once every variant has
+// been used recently the least recently used one is taken.
+// Placeholders such as {{.Weekday}} are rendered with the date and user.
+// It returns nil without error when there is no entry at all
+func SelectLog(logs []DailyLog, opts SelectOptions) (*ResolvedDailyLog, error) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	candidates := matchDate(logs, opts.Date)
 
 	if len(candidates) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	selected := candidates[rng.Intn(len(candidates))]
 
-	return &ResolvedDailyLog{
-		Day:           selected.Day,
-		ActivityLog:   pickRandom(rng, selected.ActivityLog),
-		LessonLearned: pickRandom(rng, selected.LessonLearned),
-		Obstacles:     pickRandom(rng, selected.Obstacles),
+	cutoff := opts.Date.AddDate(0, 0, -opts.AvoidDays).Format(dateLayout)
+	variants := []string{
+		pickFresh(rng, selected.ActivityLog, opts.Used, cutoff),
+		pickFresh(rng, selected.LessonLearned, opts.Used, cutoff),
+		pickFresh(rng, selected.Obstacles, opts.Used, cutoff),
 	}
-}
 
-func pickRandom(rng *rand.Rand, values []string) string {
-	if len(values) == 0 {
-		return ""
+	data := NewTemplateData(opts.Date, opts.User)
+	rendered := make([]string, len(variants))
+	for i, v := range variants {
+		text, err := renderTemplate(v, data)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] %w", selected.Day, err)
+		}
+		rendered[i] = text
 	}
-	return values[rng.Intn(len(values))]
-}
 
-// ValidateDailyLogs checks if all log fields have at least 100 characters
-// Returns warnings for any fields that are too short
-func ValidateDailyLogs(logs []DailyLog) []string {
-	var warnings []string
-	minLength := 100
+	return &ResolvedDailyLog{
+		Day:           selected.Day,
+		ActivityLog:   rendered[0],
+		LessonLearned: rendered[1],
+		Obstacles:     rendered[2],
+		Variants:      variants,
+	}, nil
+}
 
-	for _, log := range logs {
-		if len(log.ActivityLog) == 0 {
-			warnings = append(warnings, fmt.Sprintf("[%s] activity_log kosong", log.Day))
-		}
-		for i, value := range log.ActivityLog {
-			if len(value) < minLength {
-				warnings = append(warnings, fmt.Sprintf("[%s] activity_log[%d] terlalu pendek (%d karakter, minimal %d)", log.Day, i, len(value), minLength))
+// matchDate returns the highest-precedence entries for date
+func matchDate(logs []DailyLog, date time.Time) []DailyLog {
+	day := date.Format(dateLayout)
+	weekday := date.Weekday().String()
+
+	var exact, ranged, byWeekday, fallback, undated []DailyLog
+	shortest := 0
+	for _, l := range logs {
+		if !isDateKey(l.Day) {
+			undated = append(undated, l)
+			switch l.Day {
+			case weekday:
+				byWeekday = append(byWeekday, l)
+			case "Fallback":
+				fallback = append(fallback, l)
 			}
+			continue
 		}
 
-		if len(log.LessonLearned) == 0 {
-			warnings = append(warnings, fmt.Sprintf("[%s] lesson_learned kosong", log.Day))
+		spec, err := parseDateSpec(l.Day)
+		if err != nil || !spec.Contains(day) {
+			continue
 		}
-		for i, value := range log.LessonLearned {
-			if len(value) < minLength {
-				warnings = append(warnings, fmt.Sprintf("[%s] lesson_learned[%d] terlalu pendek (%d karakter, minimal %d)", log.Day, i, len(value), minLength))
-			}
+		if !spec.IsRange() {
+			exact = append(exact, l)
+			continue
 		}
-
-		if len(log.Obstacles) == 0 {
-			warnings = append(warnings, fmt.Sprintf("[%s] obstacles kosong", log.Day))
-		}
-		for i, value := range log.Obstacles {
-			if len(value) < minLength {
-				warnings = append(warnings, fmt.Sprintf("[%s] obstacles[%d] terlalu pendek (%d karakter, minimal %d)", log.Day, i, len(value), minLength))
-			}
+		switch days := spec.Days(); {
+		case len(ranged) == 0 || days < shortest:
+			ranged = []DailyLog{l}
+			shortest = days
+		case days == shortest:
+			ranged = append(ranged, l)
 		}
 	}
 
-	return warnings
+	// Date-specific entries never stand in for other days
+	for _, candidates := range [][]DailyLog{exact, ranged, byWeekday, fallback, undated} {
+		if len(candidates) > 0 {
+			return candidates
+		}
+	}
+	return nil
 }
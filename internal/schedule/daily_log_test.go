@@ -0,0 +1,86 @@
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadDailyLogsFormatsAgree(t *testing.T) {
+	files := map[string]string{
+		"logs.json": `{"days": {
+			"Monday": {
+				"activity_log": ["Wrote the importer.", "Reviewed a pull request."],
+				"lesson_learned": ["Small commits review faster."],
+				"obstacles": ["None."]
+			},
+			"Fallback": {"activity_log": ["Worked on the backlog."], "lesson_learned": ["Planning helps."], "obstacles": ["None."]}
+		}}`,
+		"logs.yaml": `days:
+  Monday:
+    activity_log:
+      - Wrote the importer.
+      - Reviewed a pull request.
+    lesson_learned: |
+      Small commits review faster.
+    obstacles: None.
+  Fallback:
+    activity_log: [Worked on the backlog.]
+    lesson_learned: Planning helps.
+    obstacles: "None."
+`,
+		"logs.md": `# Daily Logs
+
+## Monday
+### Activity Log
+- Wrote the importer.
+- Reviewed a pull request.
+### Lesson Learned
+- Small commits
+  review faster.
+### Obstacles
+- None.
+
+## Fallback
+### Activity Log
+- Worked on the backlog.
+### Lesson Learned
+- Planning helps.
+### Obstacles
+- None.
+`,
+	}
+
+	want := []DailyLog{
+		{Day: "Fallback", ActivityLog: []string{"Worked on the backlog."}, LessonLearned: []string{"Planning helps."}, Obstacles: []string{"None."}},
+		{Day: "Monday", ActivityLog: []string{"Wrote the importer.", "Reviewed a pull request."}, LessonLearned: []string{"Small commits review faster."}, Obstacles: []string{"None."}},
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadDailyLogs(path)
+			if err != nil {
+				t.Fatalf("LoadDailyLogs: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("LoadDailyLogs = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadDailyLogsRejectsEmptyDays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.yaml")
+	if err := os.WriteFile(path, []byte("days:\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDailyLogs(path); err == nil {
+		t.Error("LoadDailyLogs accepted a file without days")
+	}
+}
@@ -0,0 +1,65 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayout is the YYYY-MM-DD layout used by every date key in the config files
+const dateLayout = "2006-01-02"
+
+// dateSpec is a single date or an inclusive date range such as
+// "2026-11-01..2026-11-07"
+type dateSpec struct {
+	From string
+	To   string
+}
+
+// parseDateSpec parses "YYYY-MM-DD" or "YYYY-MM-DD..YYYY-MM-DD"
+func parseDateSpec(value string) (dateSpec, error) {
+	from, to, isRange := strings.Cut(strings.TrimSpace(value), "..")
+	from = strings.TrimSpace(from)
+	to = strings.TrimSpace(to)
+	if !isRange {
+		to = from
+	}
+
+	start, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return dateSpec{}, fmt.Errorf("tanggal %q tidak valid, gunakan format YYYY-MM-DD", from)
+	}
+	end, err := time.Parse(dateLayout, to)
+	if err != nil {
+		return dateSpec{}, fmt.Errorf("tanggal %q tidak valid, gunakan format YYYY-MM-DD", to)
+	}
+	if end.Before(start) {
+		return dateSpec{}, fmt.Errorf("rentang %q terbalik: akhir sebelum awal", value)
+	}
+
+	return dateSpec{From: from, To: to}, nil
+}
+
+// Contains reports whether date (YYYY-MM-DD) falls inside the spec
+func (d dateSpec) Contains(date string) bool {
+	// YYYY-MM-DD strings sort chronologically
+	return date >= d.From && date <= d.To
+}
+
+// Days returns the number of days covered by the spec
+func (d dateSpec) Days() int {
+	from, _ := time.Parse(dateLayout, d.From)
+	to, _ := time.Parse(dateLayout, d.To)
+	return int(to.Sub(from).Hours()/24) + 1
+}
+
+// isDateKey reports whether a daily log key is a date or date range
+// rather than a weekday name
+func isDateKey(key string) bool {
+	return key != "" && key[0] >= '0' && key[0] <= '9'
+}
+
+// IsRange reports whether the spec covers more than one day
+func (d dateSpec) IsRange() bool {
+	return d.From != d.To
+}
@@ -0,0 +1,113 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"maganghub-autopresence/internal/api"
+)
+
+// LeaveEntry is a planned absence for one date or date range
+type LeaveEntry struct {
+	Status api.Status
+	Reason string
+}
+
+// LeaveCalendar maps dates to planned absences (sick days, permits, leave)
+type LeaveCalendar struct {
+	entries []leaveSpan
+}
+
+type leaveSpan struct {
+	dates dateSpec
+	entry LeaveEntry
+}
+
+type leaveFileEntry struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// LoadLeaveCalendar loads a leave file keyed by date or date range, e.g.
+//
+//	{"2026-10-20": {"status": "SICK", "reason": "Demam"}}
+//
+// A missing file yields an empty calendar
+func LoadLeaveCalendar(filepath string) (*LeaveCalendar, error) {
+	data, err := os.ReadFile(filepath)
+	if errors.Is(err, os.ErrNotExist) {
+		return &LeaveCalendar{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]leaveFileEntry
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("format %s tidak valid: %w", filepath, err)
+	}
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	cal := &LeaveCalendar{}
+	for _, key := range keys {
+		dates, err := parseDateSpec(key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath, err)
+		}
+
+		status, err := api.ParseStatus(raw[key].Status)
+		if err != nil {
+			return nil, fmt.Errorf("%s [%s]: %w", filepath, key, err)
+		}
+		if status == api.StatusPresent {
+			return nil, fmt.Errorf("%s [%s]: status PRESENT tidak perlu dicatat di file cuti", filepath, key)
+		}
+
+		reason := strings.TrimSpace(raw[key].Reason)
+		if reason == "" {
+			return nil, fmt.Errorf("%s [%s]: reason wajib diisi", filepath, key)
+		}
+
+		cal.entries = append(cal.entries, leaveSpan{
+			dates: dates,
+			entry: LeaveEntry{Status: status, Reason: reason},
+		})
+	}
+
+	return cal, nil
+}
+
+// Lookup returns the planned absence for date (YYYY-MM-DD), if any.
+// A single-date entry wins over a range that also covers the date
+func (c *LeaveCalendar) Lookup(date string) (*LeaveEntry, bool) {
+	var match *leaveSpan
+	for i := range c.entries {
+		span := &c.entries[i]
+		if !span.dates.Contains(date) {
+			continue
+		}
+		if match == nil || (match.dates.IsRange() && !span.dates.IsRange()) {
+			match = span
+		}
+	}
+
+	if match == nil {
+		return nil, false
+	}
+	entry := match.entry
+	return &entry, true
+}
+
+// Len returns the number of entries in the calendar
+func (c *LeaveCalendar) Len() int {
+	return len(c.entries)
+}
@@ -0,0 +1,396 @@
+package schedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LintDailyLogs checks a daily log document: syntax and shape, unknown day
+// and field keys, and then every rule in ValidateDailyLogs. JSON findings
+// carry line/column positions; YAML and Markdown findings are located by
+// day only. Findings are sorted by position
+func LintDailyLogs(file string, data []byte, rules Rules) []Finding {
+	var byDay dailyLogsByDay
+	var findings []Finding
+	offsets := make(map[string]int64)
+
+	if DailyLogFormat(file) == "json" {
+		if err := json.Unmarshal(data, &byDay); err != nil {
+			return []Finding{syntaxFinding(data, err)}
+		}
+		offsets = dayKeyOffsets(data)
+		findings = unknownFieldFindings(data, offsets)
+	} else {
+		var err error
+		if byDay, err = decodeDailyLogs(file, data); err != nil {
+			f := Finding{Severity: SeverityError, Index: -1, Rule: "syntax", Message: err.Error()}
+			// Parse errors start with "baris N: "
+			if _, scanErr := fmt.Sscanf(f.Message, "baris %d:", &f.Line); scanErr == nil {
+				f.Column = 1
+				_, f.Message, _ = strings.Cut(f.Message, ": ")
+			}
+			return []Finding{f}
+		}
+		// Without offsets findings keep the canonical day order
+		days := make([]string, 0, len(byDay.Days))
+		for day := range byDay.Days {
+			days = append(days, day)
+		}
+		sort.Slice(days, func(i, j int) bool { return dayOrder(days[i], days[j]) })
+		for i, day := range days {
+			offsets[day] = int64(i)
+		}
+	}
+
+	if len(byDay.Days) == 0 {
+		return []Finding{{
+			Severity: SeverityError, Index: -1, Rule: "structure", Line: 1, Column: 1,
+			Message: "field days wajib ada dan tidak boleh kosong",
+		}}
+	}
+
+	locate := func(day string) (int, int) {
+		if DailyLogFormat(file) != "json" {
+			return 0, 0
+		}
+		return position(data, offsets[day])
+	}
+
+	var logs []DailyLog
+
+	for day, entry := range byDay.Days {
+		line, col := locate(day)
+		if msg := checkDayKey(day); msg != "" {
+			findings = append(findings, Finding{
+				Severity: SeverityError, Day: day, Index: -1, Rule: "day_key",
+				Message: msg, Line: line, Column: col,
+			})
+			continue
+		}
+
+		dayFindings := ValidateDailyLogs([]DailyLog{entry.toDailyLog(day)}, Rules{Fields: rules.Fields})
+		for _, f := range dayFindings {
+			f.Line, f.Column = line, col
+			findings = append(findings, f)
+		}
+		logs = append(logs, entry.toDailyLog(day))
+	}
+
+	if rules.CheckDuplicates {
+		sort.Slice(logs, func(i, j int) bool { return offsets[logs[i].Day] < offsets[logs[j].Day] })
+		for _, f := range ValidateDailyLogs(logs, Rules{CheckDuplicates: true}) {
+			if f.Rule != "duplicate" {
+				continue
+			}
+			f.Line, f.Column = locate(f.Day)
+			findings = append(findings, f)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line == 0 && findings[j].Line == 0 {
+			return offsets[findings[i].Day] < offsets[findings[j].Day]
+		}
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+	return findings
+}
+
+// FormatDailyLogs rewrites a JSON daily log document with days ordered
+// Monday..Sunday, Fallback, then dates chronologically, fields in
+// submission order and four-space indentation, like the bundled file. Documents with unknown
+// keys are rejected rather than silently dropping them
+func FormatDailyLogs(data []byte) ([]byte, error) {
+	byDay, err := decodeStrict(data)
+	if err != nil {
+		f := syntaxFinding(data, err)
+		return nil, fmt.Errorf("%d:%d: %s", f.Line, f.Column, f.Message)
+	}
+
+	days := make([]string, 0, len(byDay.Days))
+	for day := range byDay.Days {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return dayOrder(days[i], days[j]) })
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n    \"days\": {")
+	for i, day := range days {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, _ := json.Marshal(day)
+		fmt.Fprintf(&buf, "\n        %s: ", key)
+
+		var entry bytes.Buffer
+		enc := json.NewEncoder(&entry)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("        ", "    ")
+		if err := enc.Encode(byDay.Days[day].normalized()); err != nil {
+			return nil, err
+		}
+		buf.Write(bytes.TrimRight(entry.Bytes(), "\n"))
+	}
+	buf.WriteString("\n    }\n}\n")
+
+	return buf.Bytes(), nil
+}
+
+// normalized replaces missing fields with empty lists so they format as []
+func (e dailyLogEntry) normalized() dailyLogEntry {
+	for _, field := range []*[]string{&e.ActivityLog, &e.LessonLearned, &e.Obstacles} {
+		if *field == nil {
+			*field = []string{}
+		}
+	}
+	return e
+}
+
+func (e dailyLogEntry) toDailyLog(day string) DailyLog {
+	return DailyLog{
+		Day:           day,
+		ActivityLog:   e.ActivityLog,
+		LessonLearned: e.LessonLearned,
+		Obstacles:     e.Obstacles,
+	}
+}
+
+// decodeStrict decodes a daily log document, rejecting unknown fields
+// and trailing data
+func decodeStrict(data []byte) (dailyLogsByDay, error) {
+	var byDay dailyLogsByDay
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&byDay); err != nil {
+		return dailyLogsByDay{}, err
+	}
+	if dec.More() {
+		// An empty message marks trailing data, see syntaxFinding
+		return dailyLogsByDay{}, &json.SyntaxError{Offset: dec.InputOffset()}
+	}
+	return byDay, nil
+}
+
+// unknownFieldFindings reports every key the loader would ignore, which
+// is usually a typo such as "activity_logs"
+func unknownFieldFindings(data []byte, offsets map[string]int64) []Finding {
+	var doc map[string]json.RawMessage
+	if json.Unmarshal(data, &doc) != nil {
+		return nil
+	}
+
+	var findings []Finding
+	for key := range doc {
+		if key != "days" {
+			line, col := position(data, int64(bytes.Index(data, []byte(`"`+key+`"`))))
+			findings = append(findings, Finding{
+				Severity: SeverityError, Index: -1, Rule: "structure", Line: line, Column: col,
+				Message: fmt.Sprintf("field %q tidak dikenal", key),
+			})
+		}
+	}
+
+	var days map[string]map[string]json.RawMessage
+	if json.Unmarshal(doc["days"], &days) != nil {
+		return findings
+	}
+	for day, fields := range days {
+		for field := range fields {
+			if isLogField(field) {
+				continue
+			}
+			// The field is the first occurrence after its day key
+			start := offsets[day]
+			at := start + int64(bytes.Index(data[start:], []byte(`"`+field+`"`)))
+			line, col := position(data, at)
+			findings = append(findings, Finding{
+				Severity: SeverityError, Day: day, Index: -1, Rule: "structure", Line: line, Column: col,
+				Message: fmt.Sprintf("field %q tidak dikenal", field),
+			})
+		}
+	}
+	return findings
+}
+
+// syntaxFinding turns a JSON decode error into a finding with the
+// position the decoder reported
+func syntaxFinding(data []byte, err error) Finding {
+	f := Finding{Severity: SeverityError, Index: -1, Rule: "syntax", Message: err.Error()}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		f.Line, f.Column = position(data, syntaxErr.Offset)
+		if syntaxErr.Error() == "" {
+			f.Message = "data tambahan setelah objek JSON"
+		}
+	case errors.As(err, &typeErr):
+		f.Rule = "structure"
+		f.Line, f.Column = position(data, typeErr.Offset)
+		f.Message = fmt.Sprintf("%s harus berupa %s, bukan %s", typeErr.Field, jsonKind(typeErr.Type.String()), typeErr.Value)
+	default:
+		// Unknown field errors carry no offset
+		f.Rule = "structure"
+		f.Line, f.Column = 1, 1
+		if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			f.Line, f.Column = position(data, int64(bytes.Index(data, []byte(name))))
+			f.Message = fmt.Sprintf("field %s tidak dikenal", name)
+		}
+	}
+	return f
+}
+
+func jsonKind(goType string) string {
+	switch {
+	case strings.HasPrefix(goType, "[]"):
+		return "array"
+	case goType == "string":
+		return "string"
+	default:
+		return "object"
+	}
+}
+
+// dayKeyOffsets returns the byte offset of every key in the "days" object
+func dayKeyOffsets(data []byte) map[string]int64 {
+	offsets := make(map[string]int64)
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return offsets
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return offsets
+		}
+		if tok != "days" {
+			var skip json.RawMessage
+			if dec.Decode(&skip) != nil {
+				return offsets
+			}
+			continue
+		}
+
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return offsets
+		}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return offsets
+			}
+			key, _ := tok.(string)
+			end := dec.InputOffset()
+			offsets[key] = int64(bytes.LastIndexByte(data[:end-1], '"'))
+
+			var skip json.RawMessage
+			if dec.Decode(&skip) != nil {
+				return offsets
+			}
+		}
+		return offsets
+	}
+	return offsets
+}
+
+// position converts a byte offset into a 1-based line and column
+func position(data []byte, offset int64) (line, col int) {
+	offset = max(0, min(offset, int64(len(data))))
+	before := data[:offset]
+	line = bytes.Count(before, []byte{'\n'}) + 1
+	col = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// checkDayKey returns why key is not a usable day key, or "" when it is
+func checkDayKey(key string) string {
+	if isDateKey(key) {
+		if _, err := parseDateSpec(key); err != nil {
+			return err.Error()
+		}
+		return ""
+	}
+	if weekdayIndex(key) >= 0 {
+		return ""
+	}
+
+	msg := fmt.Sprintf("hari %q tidak dikenal", key)
+	if suggestion := closestDayName(key); suggestion != "" {
+		msg += fmt.Sprintf(", maksudnya %q?", suggestion)
+	}
+	return msg
+}
+
+// dayNames are the non-date keys matchDate understands, in display order
+var dayNames = []string{
+	time.Monday.String(), time.Tuesday.String(), time.Wednesday.String(),
+	time.Thursday.String(), time.Friday.String(), time.Saturday.String(),
+	time.Sunday.String(), "Fallback",
+}
+
+func weekdayIndex(key string) int {
+	for i, name := range dayNames {
+		if name == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// closestDayName suggests the day name within two edits of key, if any
+func closestDayName(key string) string {
+	best, bestDist := "", 3
+	for _, name := range dayNames {
+		if d := editDistance(strings.ToLower(key), strings.ToLower(name)); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// dayOrder sorts weekdays first, then Fallback, then dates
+func dayOrder(a, b string) bool {
+	ia, ib := weekdayIndex(a), weekdayIndex(b)
+	switch {
+	case ia >= 0 && ib >= 0:
+		return ia < ib
+	case ia >= 0:
+		return true
+	case ib >= 0:
+		return false
+	default:
+		return a < b
+	}
+}
@@ -0,0 +1,150 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseMarkdownLogs parses the Markdown daily log format:
+//
+//	# Daily Logs            (optional title, ignored)
+//
+//	## Monday
+//
+//	### Activity Log
+//	- First variant, which may wrap
+//	  onto following lines.
+//	- Second variant.
+//
+//	### Lesson Learned
+//	...
+//
+// Level-2 headings name the day key, level-3 headings name the field and
+// each bullet is one variant. Prose before the first day is ignored
+func parseMarkdownLogs(data []byte) (dailyLogsByDay, error) {
+	byDay := dailyLogsByDay{Days: make(map[string]dailyLogEntry)}
+
+	var day, field string
+	var bullet *strings.Builder
+	inComment := false
+
+	flush := func() {
+		if bullet == nil {
+			return
+		}
+		entry := byDay.Days[day]
+		value := strings.TrimSpace(bullet.String())
+		switch field {
+		case "activity_log":
+			entry.ActivityLog = append(entry.ActivityLog, value)
+		case "lesson_learned":
+			entry.LessonLearned = append(entry.LessonLearned, value)
+		case "obstacles":
+			entry.Obstacles = append(entry.Obstacles, value)
+		}
+		byDay.Days[day] = entry
+		bullet = nil
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lineNo := i + 1
+		text := strings.TrimSpace(line)
+
+		// HTML comments may hold notes that are never submitted
+		if inComment || strings.HasPrefix(text, "<!--") {
+			inComment = !strings.Contains(text, "-->")
+			continue
+		}
+
+		if level, title, ok := markdownHeading(text); ok {
+			flush()
+			switch level {
+			case 1:
+				// Document title
+			case 2:
+				if _, dup := byDay.Days[title]; dup {
+					return dailyLogsByDay{}, fmt.Errorf("baris %d: hari %q duplikat", lineNo, title)
+				}
+				day, field = title, ""
+				byDay.Days[day] = dailyLogEntry{}
+			case 3:
+				if day == "" {
+					return dailyLogsByDay{}, fmt.Errorf("baris %d: field %q di luar heading hari (##)", lineNo, title)
+				}
+				field = markdownField(title)
+				if field == "" {
+					return dailyLogsByDay{}, fmt.Errorf("baris %d: field %q tidak dikenal, gunakan Activity Log, Lesson Learned atau Obstacles", lineNo, title)
+				}
+			default:
+				return dailyLogsByDay{}, fmt.Errorf("baris %d: heading level %d tidak didukung", lineNo, level)
+			}
+			continue
+		}
+
+		if text == "" {
+			flush()
+			continue
+		}
+
+		if item, ok := markdownBullet(line); ok {
+			flush()
+			if field == "" {
+				return dailyLogsByDay{}, fmt.Errorf("baris %d: bullet di luar field (###)", lineNo)
+			}
+			bullet = &strings.Builder{}
+			bullet.WriteString(item)
+			continue
+		}
+
+		switch {
+		case bullet != nil:
+			// Wrapped bullet text joins the previous line
+			bullet.WriteString(" ")
+			bullet.WriteString(text)
+		case day == "":
+			// Introductory prose
+		default:
+			return dailyLogsByDay{}, fmt.Errorf("baris %d: teks harus berada di dalam bullet (- ...)", lineNo)
+		}
+	}
+	flush()
+
+	return byDay, nil
+}
+
+// markdownHeading parses an ATX heading such as "## Monday"
+func markdownHeading(text string) (level int, title string, ok bool) {
+	level = len(text) - len(strings.TrimLeft(text, "#"))
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	rest := text[level:]
+	if rest != "" && rest[0] != ' ' {
+		return 0, "", false
+	}
+	title = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(rest), "#"))
+	return level, title, title != ""
+}
+
+// markdownBullet returns the text of an unindented "-", "*" or "+" bullet
+func markdownBullet(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 1 || len(trimmed) < 2 {
+		return "", false
+	}
+	if !strings.ContainsRune("-*+", rune(trimmed[0])) || trimmed[1] != ' ' {
+		return "", false
+	}
+	return strings.TrimSpace(trimmed[2:]), true
+}
+
+// markdownField maps a heading like "Activity Log" or "activity_log" to
+// its JSON field name, or "" when it names no field
+func markdownField(title string) string {
+	name := strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(title, "_", " ")), "_"))
+	if isLogField(name) {
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package schedule
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMarkdownLogs(t *testing.T) {
+	in := `# Daily Logs
+
+Intro prose before the first day is ignored.
+
+## Monday
+
+### Activity Log
+- First variant, which wraps
+  onto the next line.
+* Second variant.
+
+<!-- a note
+that spans lines -->
+### lesson_learned
++ Lesson.
+
+### Obstacles ###
+- None.
+
+## 2026-10-20
+### Activity Log
+- Dated entry.
+`
+	got, err := parseMarkdownLogs([]byte(strings.ReplaceAll(in, "\n", "\r\n")))
+	if err != nil {
+		t.Fatalf("parseMarkdownLogs: %v", err)
+	}
+
+	want := dailyLogsByDay{Days: map[string]dailyLogEntry{
+		"Monday": {
+			ActivityLog:   []string{"First variant, which wraps onto the next line.", "Second variant."},
+			LessonLearned: []string{"Lesson."},
+			Obstacles:     []string{"None."},
+		},
+		"2026-10-20": {ActivityLog: []string{"Dated entry."}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMarkdownLogs = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseMarkdownLogsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"duplicate day", "## Monday\n## Monday\n", `baris 2: hari "Monday" duplikat`},
+		{"field outside a day", "### Activity Log\n", "baris 1: field \"Activity Log\" di luar heading hari"},
+		{"unknown field", "## Monday\n### Notes\n", `baris 2: field "Notes" tidak dikenal`},
+		{"deep heading", "## Monday\n#### Deep\n", "baris 2: heading level 4 tidak didukung"},
+		{"bullet outside a field", "## Monday\n- item\n", "baris 2: bullet di luar field"},
+		{"prose inside a day", "## Monday\n### Obstacles\n\nloose text\n", "baris 4: teks harus berada di dalam bullet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseMarkdownLogs([]byte(tt.in))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseMarkdownLogs error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
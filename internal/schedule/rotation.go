@@ -0,0 +1,85 @@
+package schedule
+
+import (
+	"math/rand"
+	"strings"
+
+	"maganghub-autopresence/internal/history"
+)
+
+// UsageHistory maps a daily log variant to the date (YYYY-MM-DD) it was last submitted
+type UsageHistory map[string]string
+
+// UsageFromHistory builds a UsageHistory from submitted runs
+func UsageFromHistory(entries []history.Entry) UsageHistory {
+	used := make(UsageHistory)
+	for _, e := range entries {
+		if e.Outcome != history.OutcomeSubmitted {
+			continue
+		}
+		// Templated variants render differently each day, so match on the raw text
+		texts := e.Variants
+		if len(texts) == 0 && e.Log != nil {
+			texts = []string{e.Log.ActivityLog, e.Log.LessonLearned, e.Log.Obstacles}
+		}
+		for _, text := range texts {
+			used.mark(text, e.Date)
+		}
+	}
+	return used
+}
+
+func (u UsageHistory) mark(text, date string) {
+	key := usageKey(text)
+	if key == "" {
+		return
+	}
+	if date > u[key] {
+		u[key] = date
+	}
+}
+
+// lastUsed returns the last date text was submitted, or "" if never
+func (u UsageHistory) lastUsed(text string) string {
+	if u == nil {
+		return ""
+	}
+	return u[usageKey(text)]
+}
+
+func usageKey(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// pickFresh picks a random variant not used on or after cutoff. If every
+// variant was used since then, the least recently used one wins
+func pickFresh(rng *rand.Rand, values []string, used UsageHistory, cutoff string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	var fresh []string
+	for _, v := range values {
+		if last := used.lastUsed(v); last == "" || last < cutoff {
+			fresh = append(fresh, v)
+		}
+	}
+	if len(fresh) > 0 {
+		return fresh[rng.Intn(len(fresh))]
+	}
+
+	// Everything is recent; rotate through the least recently used variants
+	var oldest []string
+	oldestDate := ""
+	for _, v := range values {
+		last := used.lastUsed(v)
+		switch {
+		case len(oldest) == 0 || last < oldestDate:
+			oldest = []string{v}
+			oldestDate = last
+		case last == oldestDate:
+			oldest = append(oldest, v)
+		}
+	}
+	return oldest[rng.Intn(len(oldest))]
+}
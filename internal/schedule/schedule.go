@@ -1,9 +1,16 @@
 package schedule
 
 import (
+	"errors"
 	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"maganghub-autopresence/internal/api"
+	"maganghub-autopresence/internal/history"
+
 	"github.com/robfig/cron/v3"
 )
 
@@ -11,58 +18,208 @@ import (
 type Scheduler struct {
 	cron           *cron.Cron
 	cronExpression string
+	window         *Window
+	spec           cron.Schedule
+	job            Job
+	done           chan struct{}
+	stopOnce       sync.Once
+
+	// Catch-up state, see EnableCatchUp
+	state *RunState
+	grace time.Duration
+
+	// running serialises job runs and busy tells whether one is in
+	// progress without touching the lock; mu guards alertedDate
+	running     sync.Mutex
+	busy        atomic.Bool
+	mu          sync.Mutex
+	alertedDate string
 }
 
-// NewScheduler creates a new scheduler with the given cron expression
-func NewScheduler(cronExpression string) *Scheduler {
+// Job is an attendance run. trigger says what started it; returning nil
+// means today needs no further runs
+type Job func(trigger history.Trigger) error
+
+// NewScheduler creates a new scheduler with the given cron expression,
+// evaluated in WIB like every attendance date. When window is set, the
+// cron expression only picks the days and the time of day is drawn from
+// the window instead
+func NewScheduler(cronExpression string, window *Window) *Scheduler {
 	return &Scheduler{
-		cron:           cron.New(),
+		cron:           cron.New(cron.WithLocation(api.Location)),
 		cronExpression: cronExpression,
+		window:         window,
+		done:           make(chan struct{}),
 	}
 }
 
-// Start starts the scheduler with the given job function
-func (s *Scheduler) Start(job func()) error {
-	_, err := s.cron.AddFunc(s.cronExpression, func() {
-		log.Println("Running scheduled job...")
-		job()
-		log.Println("Scheduled job completed")
-		log.Printf("Next scheduled run: %s", s.GetNextRun())
-	})
+// Start starts the scheduler with the given job function. A job that
+// returns nil counts as today's successful run
+func (s *Scheduler) Start(job Job) error {
+	spec, err := s.parseSpec()
 	if err != nil {
 		return err
 	}
+	s.spec = spec
+	s.job = job
+
+	s.cron.Schedule(spec, cron.FuncJob(func() {
+		s.runJob("Scheduled", history.TriggerCron, job)
+	}))
+
+	if s.window != nil {
+		log.Printf("Scheduler started with cron expression: %s, run window %s", s.cronExpression, s.window)
+	} else {
+		log.Printf("Scheduler started with cron expression: %s", s.cronExpression)
+	}
 
-	log.Printf("Scheduler started with cron expression: %s", s.cronExpression)
 	s.cron.Start()
+	if s.state != nil {
+		go s.watchWake()
+	}
+	s.logNextRun()
+	return nil
+}
+
+// runJob runs a job, one at a time, and records success for catch-up.
+// kind names the trigger in the log, e.g. "Scheduled"
+func (s *Scheduler) runJob(kind string, trigger history.Trigger, job Job) {
+	s.running.Lock()
+	defer s.running.Unlock()
+	s.execute(kind, trigger, job)
+}
+
+// execute runs job and records success; the caller holds s.running
+func (s *Scheduler) execute(kind string, trigger history.Trigger, job Job) {
+	s.busy.Store(true)
+	defer s.busy.Store(false)
+
+	log.Printf("Running %s job...", strings.ToLower(kind))
+	err := job(trigger)
+	if errors.Is(err, ErrDryRun) {
+		log.Println("🧪 Dry run, today is not marked as done")
+	}
+	if err == nil && s.state != nil {
+		if err := s.state.MarkSuccess(api.Today()); err != nil {
+			log.Printf("Warning: Failed to save run state: %v", err)
+		}
+	}
+	log.Printf("%s job completed", kind)
+	s.logNextRun()
+}
+
+// ErrDryRun is returned by a job that ran without submitting anything.
+// Like any error it keeps today from being marked done, so a real run
+// sharing the run state still submits
+var ErrDryRun = errors.New("dry run, nothing submitted")
+
+// ErrBusy is returned by RunNow while another run is in progress
+var ErrBusy = errors.New("a run is already in progress")
+
+// RunNow starts the job in the background unless a run is already in
+// progress. Call it after Start
+func (s *Scheduler) RunNow(trigger history.Trigger) error {
+	if s.job == nil {
+		return errors.New("scheduler not started")
+	}
+	if !s.running.TryLock() {
+		return ErrBusy
+	}
+	go func() {
+		defer s.running.Unlock()
+		s.execute("Manual", trigger, s.job)
+	}()
 	return nil
 }
 
-// Stop stops the scheduler
+// Busy reports whether a run is in progress
+func (s *Scheduler) Busy() bool {
+	return s.busy.Load()
+}
+
+// logNextRun logs the next run, including the drawn time when a window is used
+func (s *Scheduler) logNextRun() {
+	if s.window != nil {
+		log.Printf("🎲 Next scheduled run: %s (drawn within %s)", s.GetNextRun(), s.window)
+		return
+	}
+	log.Printf("Next scheduled run: %s", s.GetNextRun())
+}
+
+// Stop stops the scheduler and waits for a running job to return. Calling
+// it again does nothing
 func (s *Scheduler) Stop() {
-	s.cron.Stop()
-	log.Println("Scheduler stopped")
+	s.stopOnce.Do(func() {
+		close(s.done)
+		<-s.cron.Stop().Done()
+		log.Println("Scheduler stopped")
+	})
+}
+
+// parseSpec parses the cron expression, wrapped in the run window if set
+func (s *Scheduler) parseSpec() (cron.Schedule, error) {
+	spec, err := cron.ParseStandard(s.cronExpression)
+	if err != nil {
+		return nil, err
+	}
+	if s.window != nil {
+		return windowSchedule{days: spec, window: s.window}, nil
+	}
+	return spec, nil
+}
+
+// NextRunAfter returns the first scheduled run after t. Unlike GetNextRun
+// it works without starting the scheduler
+func (s *Scheduler) NextRunAfter(t time.Time) (time.Time, error) {
+	spec, err := s.parseSpec()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return spec.Next(t.In(api.Location)), nil
+}
+
+// RunsOn reports whether the schedule has a run on day's calendar date,
+// taken as a WIB date, e.g. to tell working days from days off when
+// backfilling
+func (s *Scheduler) RunsOn(day time.Time) (bool, error) {
+	spec, err := s.parseSpec()
+	if err != nil {
+		return false, err
+	}
+	y, m, d := day.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, api.Location)
+	next := spec.Next(start.Add(-time.Second))
+	return !next.IsZero() && next.Before(start.AddDate(0, 0, 1)), nil
 }
 
 // GetNextRun returns the next scheduled run time as a string
 func (s *Scheduler) GetNextRun() string {
 	entries := s.cron.Entries()
 	if len(entries) > 0 {
-		return entries[0].Next.Format("2006-01-02 15:04:05")
+		return entries[0].Next.Format("2006-01-02 15:04:05 MST")
 	}
 	return "No scheduled job"
 }
 
-// ScheduleOnce schedules a one-time job to run after the specified duration
-func (s *Scheduler) ScheduleOnce(duration time.Duration, job func()) {
-	retryTime := time.Now().Add(duration)
-	log.Printf("🔄 Rescheduling job for: %s", retryTime.Format("2006-01-02 15:04:05"))
+// Retry runs the job given to Start once more after the specified duration
+func (s *Scheduler) Retry(duration time.Duration) {
+	s.ScheduleOnce(duration, s.job)
+}
+
+// ScheduleOnce schedules a one-time job to run after the specified
+// duration. The run is dropped when the scheduler stops first
+func (s *Scheduler) ScheduleOnce(duration time.Duration, job Job) {
+	retryTime := time.Now().Add(duration).In(api.Location)
+	log.Printf("🔄 Rescheduling job for: %s", retryTime.Format("2006-01-02 15:04:05 MST"))
 
 	go func() {
-		time.Sleep(duration)
-		log.Println("Running rescheduled job...")
-		job()
-		log.Println("Rescheduled job completed")
-		log.Printf("Next scheduled run: %s", s.GetNextRun())
+		timer := time.NewTimer(duration)
+		defer timer.Stop()
+		select {
+		case <-s.done:
+			return
+		case <-timer.C:
+			s.runJob("Rescheduled", history.TriggerRetry, job)
+		}
 	}()
 }
@@ -0,0 +1,235 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+	"maganghub-autopresence/internal/gitlog"
+)
+
+// LogSource resolves the daily log to submit for a date. Resolve returns
+// nil without error when the source has nothing for that date, so a
+// ChainSource can fall through to the next source
+type LogSource interface {
+	Resolve(ctx context.Context, date time.Time, user *api.User) (*ResolvedDailyLog, error)
+}
+
+// FileSource picks a log from a daily log file (JSON, YAML or Markdown)
+type FileSource struct {
+	Path string
+	// Used and AvoidDays are passed to SelectLog to avoid repeats
+	Used      UsageHistory
+	AvoidDays int
+}
+
+// Resolve implements LogSource
+func (s *FileSource) Resolve(ctx context.Context, date time.Time, user *api.User) (*ResolvedDailyLog, error) {
+	logs, err := LoadDailyLogs(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	return SelectLog(logs, SelectOptions{Date: date, Used: s.Used, AvoidDays: s.AvoidDays, User: user})
+}
+
+// DirSource reads one file per date from Dir, named like 2026-10-20.json,
+// .yaml, .yml or .md. A file holds the fields of a single day, e.g.
+//
+//	{"activity_log": ["..."], "lesson_learned": ["..."], "obstacles": ["..."]}
+//
+// or in Markdown the "### Activity Log" sections without a day heading.
+// A date without a file resolves to nil
+type DirSource struct {
+	Dir       string
+	Used      UsageHistory
+	AvoidDays int
+}
+
+// dirExtensions are tried in order for each date
+var dirExtensions = []string{".json", ".yaml", ".yml", ".md"}
+
+// Resolve implements LogSource
+func (s *DirSource) Resolve(ctx context.Context, date time.Time, user *api.User) (*ResolvedDailyLog, error) {
+	day := date.Format(dateLayout)
+	for _, ext := range dirExtensions {
+		file := filepath.Join(s.Dir, day+ext)
+		data, err := os.ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		byDay, err := decodeDayFile(file, day, data)
+		if err != nil {
+			return nil, fmt.Errorf("format %s tidak valid: %w", filepath.Base(file), err)
+		}
+		entry := byDay.Days[day]
+		logs := []DailyLog{entry.toDailyLog(day)}
+		return SelectLog(logs, SelectOptions{Date: date, Used: s.Used, AvoidDays: s.AvoidDays, User: user})
+	}
+	return nil, nil
+}
+
+// decodeDayFile decodes a single-day file by wrapping it into a daily log
+// document keyed by day
+func decodeDayFile(file, day string, data []byte) (dailyLogsByDay, error) {
+	switch DailyLogFormat(file) {
+	case "markdown":
+		return parseMarkdownLogs(append([]byte("## "+day+"\n"), data...))
+	case "yaml":
+		root, err := parseYAML(data)
+		if err != nil {
+			return dailyLogsByDay{}, err
+		}
+		entry, err := decodeYAMLEntry(root)
+		if err != nil {
+			return dailyLogsByDay{}, err
+		}
+		return dailyLogsByDay{Days: map[string]dailyLogEntry{day: entry}}, nil
+	default:
+		key, _ := json.Marshal(day)
+		doc := fmt.Sprintf(`{"days": {%s: %s}}`, key, data)
+		return decodeDailyLogs(file, []byte(doc))
+	}
+}
+
+// CommandSource runs Command through the shell and reads the log from its
+// stdout as JSON: {"activity_log": "...", "lesson_learned": "...",
+// "obstacles": "..."}. The date and profile are passed in MAGANGHUB_DATE,
+// MAGANGHUB_WEEKDAY, MAGANGHUB_WEEK, MAGANGHUB_JOB_ROLE and
+// MAGANGHUB_COMPANY. Empty output resolves to nil
+type CommandSource struct {
+	Command string
+	Timeout time.Duration
+}
+
+type commandOutput struct {
+	ActivityLog   string `json:"activity_log"`
+	LessonLearned string `json:"lesson_learned"`
+	Obstacles     string `json:"obstacles"`
+}
+
+// Resolve implements LogSource
+func (s *CommandSource) Resolve(ctx context.Context, date time.Time, user *api.User) (*ResolvedDailyLog, error) {
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	data := NewTemplateData(date, user)
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.Command)
+	cmd.Env = append(os.Environ(),
+		"MAGANGHUB_DATE="+data.Date,
+		"MAGANGHUB_WEEKDAY="+data.Weekday,
+		fmt.Sprintf("MAGANGHUB_WEEK=%d", data.Week),
+		"MAGANGHUB_JOB_ROLE="+data.JobRole,
+		"MAGANGHUB_COMPANY="+data.Company,
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("log command: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("log command: %w", err)
+	}
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil, nil
+	}
+
+	var out commandOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("output log command bukan JSON yang valid: %w", err)
+	}
+	return &ResolvedDailyLog{
+		Day:           "Command",
+		ActivityLog:   strings.TrimSpace(out.ActivityLog),
+		LessonLearned: strings.TrimSpace(out.LessonLearned),
+		Obstacles:     strings.TrimSpace(out.Obstacles),
+	}, nil
+}
+
+// GitSource replaces the activity log resolved by Base with a summary of
+// the day's commits in Repos. Days without commits, or repos that cannot
+// be read, keep Base's log
+type GitSource struct {
+	Base     LogSource
+	Repos    []string
+	Author   string
+	Template string
+}
+
+// Resolve implements LogSource
+func (s *GitSource) Resolve(ctx context.Context, date time.Time, user *api.User) (*ResolvedDailyLog, error) {
+	resolved, err := s.Base.Resolve(ctx, date, user)
+	if err != nil || resolved == nil {
+		return resolved, err
+	}
+
+	commits, err := gitlog.Commits(ctx, s.Repos, s.Author, date)
+	if err != nil {
+		log.Printf("⚠️  Warning: Failed to read git commits, keeping the written activity log: %v", err)
+		return resolved, nil
+	}
+	if len(commits) == 0 {
+		log.Println("No commits today, keeping the written activity log")
+		return resolved, nil
+	}
+
+	tmpl := s.Template
+	if tmpl == "" {
+		tmpl = DefaultCommitTemplate
+	}
+	summary, err := SummarizeCommits(tmpl, commits, NewTemplateData(date, user))
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("📦 Activity log built from %d commit(s)", len(commits))
+
+	resolved.Day = fmt.Sprintf("%s + %d commit(s)", resolved.Day, len(commits))
+	resolved.ActivityLog = summary
+	// The written activity variant was not used, so don't mark it
+	if len(resolved.Variants) > 0 {
+		resolved.Variants[0] = ""
+	}
+	return resolved, nil
+}
+
+// ChainSource tries each source in order and returns the first log found.
+// A failing source is skipped; its error is only returned when no later
+// source has a log either
+type ChainSource []LogSource
+
+// Resolve implements LogSource
+func (c ChainSource) Resolve(ctx context.Context, date time.Time, user *api.User) (*ResolvedDailyLog, error) {
+	var errs []error
+	for _, source := range c {
+		resolved, err := source.Resolve(ctx, date, user)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Printf("⚠️  Log source %T failed, trying the next one: %v", source, err)
+			errs = append(errs, err)
+			continue
+		}
+		if resolved != nil {
+			return resolved, nil
+		}
+	}
+	return nil, errors.Join(errs...)
+}
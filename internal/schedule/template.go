@@ -0,0 +1,84 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+)
+
+// TemplateData is available to text/template placeholders in daily logs,
+// e.g. "Minggu ke-{{.Week}} sebagai {{.JobRole}} di {{.Company}}"
+type TemplateData struct {
+	// Date is the submission date as YYYY-MM-DD
+	Date string
+	// DateLong is the date in Bahasa, e.g. "Senin, 20 Oktober 2026"
+	DateLong string
+	// Weekday is the day name in Bahasa, e.g. "Senin"
+	Weekday string
+	// Week is the internship week number starting at 1, or 0 if unknown
+	Week int
+	// JobRole, Company and Mentor come from the Monev profile
+	JobRole string
+	Company string
+	Mentor  string
+}
+
+var indonesianWeekdays = [...]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"}
+
+var indonesianMonths = [...]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// NewTemplateData builds the placeholder values for date and user, user may be nil
+func NewTemplateData(date time.Time, user *api.User) TemplateData {
+	weekday := indonesianWeekdays[date.Weekday()]
+	data := TemplateData{
+		Date:     date.Format(dateLayout),
+		DateLong: fmt.Sprintf("%s, %d %s %d", weekday, date.Day(), indonesianMonths[date.Month()-1], date.Year()),
+		Weekday:  weekday,
+	}
+
+	if user == nil {
+		return data
+	}
+
+	data.JobRole = user.JobRole
+	data.Company = user.InternshipCompany
+	data.Mentor = user.MentorName
+
+	if start, _, err := user.InternshipPeriod(); err == nil {
+		startDate, _ := time.Parse(dateLayout, start)
+		day, _ := time.Parse(dateLayout, data.Date)
+		if !day.Before(startDate) {
+			data.Week = int(day.Sub(startDate).Hours()/24)/7 + 1
+		}
+	}
+
+	return data
+}
+
+// renderTemplate executes text as a text/template with data. Text without
+// placeholders is returned unchanged
+func renderTemplate(text string, data TemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New("daily_log").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("template tidak valid: %w", err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("template gagal dirender: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// checkTemplate reports a template error without needing real data
+func checkTemplate(text string) error {
+	_, err := renderTemplate(text, TemplateData{})
+	return err
+}
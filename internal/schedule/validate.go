@@ -0,0 +1,278 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Severity says whether a finding blocks submission or is just advice
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a single validation result for a daily log field
+type Finding struct {
+	Severity Severity
+	Day      string
+	Field    string
+	// Index is the variant index, or -1 for findings about the whole field
+	Index   int
+	Rule    string
+	Message string
+	// Line and Column locate the finding in the source file when known
+	Line   int
+	Column int
+}
+
+// String formats the finding like "3:5: [Monday] activity_log[0] terlalu pendek (...)"
+func (f Finding) String() string {
+	var b strings.Builder
+	if f.Line > 0 {
+		fmt.Fprintf(&b, "%d:%d: ", f.Line, f.Column)
+	}
+	if f.Day != "" {
+		fmt.Fprintf(&b, "[%s] ", f.Day)
+	}
+	if f.Field != "" {
+		b.WriteString(f.Field)
+		if f.Index >= 0 {
+			fmt.Fprintf(&b, "[%d]", f.Index)
+		}
+		b.WriteString(" ")
+	}
+	b.WriteString(f.Message)
+	return b.String()
+}
+
+// FieldRules are the checks applied to every variant of one field.
+// Zero values disable a check
+type FieldRules struct {
+	MinRunes      int      `json:"min_runes"`
+	MaxRunes      int      `json:"max_runes"`
+	MinSentences  int      `json:"min_sentences"`
+	BannedPhrases []string `json:"banned_phrases"`
+}
+
+// Rules configures ValidateDailyLogs. Fields is keyed by JSON field name:
+// activity_log, lesson_learned and obstacles
+type Rules struct {
+	Fields          map[string]FieldRules `json:"fields"`
+	CheckDuplicates bool                  `json:"check_duplicates"`
+}
+
+// logFields lists the daily log fields in submission order
+var logFields = []string{"activity_log", "lesson_learned", "obstacles"}
+
+// DefaultRules require at least 100 characters per field and flag duplicates
+func DefaultRules() Rules {
+	rules := Rules{Fields: make(map[string]FieldRules), CheckDuplicates: true}
+	for _, field := range logFields {
+		rules.Fields[field] = FieldRules{MinRunes: 100}
+	}
+	return rules
+}
+
+// rulesFile is the JSON shape of a rules file. Pointers tell a key that is
+// absent, which keeps its default, from one set to zero or false
+type rulesFile struct {
+	Fields          map[string]fieldRulesFile `json:"fields"`
+	CheckDuplicates *bool                     `json:"check_duplicates"`
+}
+
+type fieldRulesFile struct {
+	MinRunes      *int     `json:"min_runes"`
+	MaxRunes      *int     `json:"max_runes"`
+	MinSentences  *int     `json:"min_sentences"`
+	BannedPhrases []string `json:"banned_phrases"`
+}
+
+// LoadRules loads validation rules from a JSON file over DefaultRules.
+// A missing file yields the defaults, and so does every key the file
+// leaves out
+func LoadRules(filepath string) (Rules, error) {
+	rules := DefaultRules()
+
+	data, err := os.ReadFile(filepath)
+	if errors.Is(err, os.ErrNotExist) {
+		return rules, nil
+	}
+	if err != nil {
+		return Rules{}, err
+	}
+
+	var file rulesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return Rules{}, fmt.Errorf("format %s tidak valid: %w", filepath, err)
+	}
+
+	for field, override := range file.Fields {
+		if !isLogField(field) {
+			return Rules{}, fmt.Errorf("%s: field %q tidak dikenal", filepath, field)
+		}
+		fieldRules := rules.Fields[field]
+		if override.MinRunes != nil {
+			fieldRules.MinRunes = *override.MinRunes
+		}
+		if override.MaxRunes != nil {
+			fieldRules.MaxRunes = *override.MaxRunes
+		}
+		if override.MinSentences != nil {
+			fieldRules.MinSentences = *override.MinSentences
+		}
+		if override.BannedPhrases != nil {
+			fieldRules.BannedPhrases = override.BannedPhrases
+		}
+		rules.Fields[field] = fieldRules
+	}
+	if file.CheckDuplicates != nil {
+		rules.CheckDuplicates = *file.CheckDuplicates
+	}
+
+	return rules, nil
+}
+
+func isLogField(field string) bool {
+	for _, f := range logFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// HasErrors reports whether any finding has error severity
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateResolved checks the rendered log that is about to be submitted
+// against the field rules. Duplicates are not checked for a single log
+func ValidateResolved(resolved *ResolvedDailyLog, rules Rules) []Finding {
+	log := DailyLog{
+		Day:           resolved.Day,
+		ActivityLog:   []string{resolved.ActivityLog},
+		LessonLearned: []string{resolved.LessonLearned},
+		Obstacles:     []string{resolved.Obstacles},
+	}
+	return ValidateDailyLogs([]DailyLog{log}, Rules{Fields: rules.Fields})
+}
+
+// ValidateDailyLogs checks every variant against the per-field rules,
+// that templated fields parse, and optionally that no variant is repeated
+func ValidateDailyLogs(logs []DailyLog, rules Rules) []Finding {
+	var findings []Finding
+	seen := make(map[string]Finding)
+
+	for _, log := range logs {
+		values := map[string][]string{
+			"activity_log":   log.ActivityLog,
+			"lesson_learned": log.LessonLearned,
+			"obstacles":      log.Obstacles,
+		}
+
+		for _, field := range logFields {
+			variants := values[field]
+			if len(variants) == 0 {
+				findings = append(findings, Finding{
+					Severity: SeverityError, Day: log.Day, Field: field, Index: -1,
+					Rule: "required", Message: "kosong",
+				})
+				continue
+			}
+
+			for i, value := range variants {
+				at := Finding{Day: log.Day, Field: field, Index: i}
+				findings = append(findings, checkVariant(at, value, rules.Fields[field])...)
+
+				if !rules.CheckDuplicates {
+					continue
+				}
+				key := strings.ToLower(usageKey(value))
+				if first, ok := seen[key]; ok {
+					at.Severity = SeverityWarning
+					at.Rule = "duplicate"
+					at.Message = fmt.Sprintf("sama dengan [%s] %s[%d]", first.Day, first.Field, first.Index)
+					findings = append(findings, at)
+				} else {
+					seen[key] = at
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// checkVariant applies field rules to one variant. at carries its location
+func checkVariant(at Finding, value string, rules FieldRules) []Finding {
+	var findings []Finding
+	add := func(severity Severity, rule, format string, args ...any) {
+		f := at
+		f.Severity = severity
+		f.Rule = rule
+		f.Message = fmt.Sprintf(format, args...)
+		findings = append(findings, f)
+	}
+
+	length := utf8.RuneCountInString(strings.TrimSpace(value))
+	if rules.MinRunes > 0 && length < rules.MinRunes {
+		add(SeverityWarning, "min_runes", "terlalu pendek (%d karakter, minimal %d)", length, rules.MinRunes)
+	}
+	if rules.MaxRunes > 0 && length > rules.MaxRunes {
+		add(SeverityError, "max_runes", "terlalu panjang (%d karakter, maksimal %d)", length, rules.MaxRunes)
+	}
+
+	if rules.MinSentences > 0 {
+		if n := countSentences(value); n < rules.MinSentences {
+			add(SeverityWarning, "min_sentences", "hanya %d kalimat, minimal %d", n, rules.MinSentences)
+		}
+	}
+
+	lower := strings.ToLower(value)
+	for _, phrase := range rules.BannedPhrases {
+		if phrase != "" && strings.Contains(lower, strings.ToLower(phrase)) {
+			add(SeverityError, "banned_phrase", "mengandung frasa terlarang %q", phrase)
+		}
+	}
+
+	if err := checkTemplate(value); err != nil {
+		add(SeverityError, "template", "%v", err)
+	}
+
+	return findings
+}
+
+// countSentences counts runs of text ended by '.', '!' or '?', plus any
+// trailing text without terminal punctuation
+func countSentences(text string) int {
+	count := 0
+	inSentence := false
+	for _, r := range text {
+		switch {
+		case r == '.' || r == '!' || r == '?':
+			if inSentence {
+				count++
+				inSentence = false
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			inSentence = true
+		}
+	}
+	if inSentence {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,192 @@
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// rulesOf returns the rule names of findings in order
+func rulesOf(findings []Finding) []string {
+	var rules []string
+	for _, f := range findings {
+		rules = append(rules, f.Field+":"+f.Rule)
+	}
+	return rules
+}
+
+func TestValidateDailyLogs(t *testing.T) {
+	long := strings.Repeat("Mengerjakan fitur baru. ", 5)
+	rules := Rules{
+		Fields: map[string]FieldRules{
+			"activity_log":   {MinRunes: 20, MaxRunes: 200, BannedPhrases: []string{"Lorem Ipsum"}},
+			"lesson_learned": {MinSentences: 2},
+		},
+		CheckDuplicates: true,
+	}
+
+	logs := []DailyLog{
+		{
+			Day:           "Monday",
+			ActivityLog:   []string{"Pendek.", strings.Repeat("x", 201), "Berisi lorem ipsum dolor sit amet."},
+			LessonLearned: []string{"Satu kalimat saja", "Dua. Kalimat!"},
+		},
+		{
+			Day:           "Tuesday",
+			ActivityLog:   []string{long, "Mengerjakan {{ .Unknown fitur hari ini"},
+			LessonLearned: []string{"Dua. Kalimat!"},
+			Obstacles:     []string{"Tidak ada."},
+		},
+	}
+
+	got := rulesOf(ValidateDailyLogs(logs, rules))
+	want := []string{
+		"activity_log:min_runes",
+		"activity_log:max_runes",
+		"activity_log:banned_phrase",
+		"lesson_learned:min_sentences",
+		"obstacles:required",
+		"activity_log:template",
+		"lesson_learned:duplicate",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings = %q, want %q", got, want)
+	}
+}
+
+func TestValidateDailyLogsSeverities(t *testing.T) {
+	rules := Rules{Fields: map[string]FieldRules{
+		"activity_log": {MinRunes: 50, MaxRunes: 5},
+	}}
+	findings := ValidateDailyLogs([]DailyLog{{
+		Day:           "Monday",
+		ActivityLog:   []string{"Terlalu panjang."},
+		LessonLearned: []string{"Ok."},
+		Obstacles:     []string{"Ok."},
+	}}, rules)
+
+	severities := map[string]Severity{}
+	for _, f := range findings {
+		severities[f.Rule] = f.Severity
+	}
+	if severities["min_runes"] != SeverityWarning || severities["max_runes"] != SeverityError {
+		t.Errorf("severities = %v, want min_runes warning and max_runes error", severities)
+	}
+	if !HasErrors(findings) {
+		t.Error("HasErrors = false with a max_runes finding")
+	}
+	if HasErrors(findings[:1]) {
+		t.Error("HasErrors = true with only a warning")
+	}
+}
+
+func TestValidateDuplicatesIgnoreCaseAndSpacing(t *testing.T) {
+	logs := []DailyLog{
+		{Day: "Monday", ActivityLog: []string{"Rapat  tim."}, LessonLearned: []string{"A."}, Obstacles: []string{"B."}},
+		{Day: "Tuesday", ActivityLog: []string{"rapat tim."}, LessonLearned: []string{"C."}, Obstacles: []string{"D."}},
+	}
+
+	findings := ValidateDailyLogs(logs, Rules{CheckDuplicates: true})
+	if len(findings) != 1 || findings[0].Rule != "duplicate" {
+		t.Fatalf("findings = %v, want one duplicate", findings)
+	}
+	if got, want := findings[0].String(), "[Tuesday] activity_log[0] sama dengan [Monday] activity_log[0]"; got != want {
+		t.Errorf("finding = %q, want %q", got, want)
+	}
+
+	if findings := ValidateDailyLogs(logs, Rules{}); len(findings) != 0 {
+		t.Errorf("findings without CheckDuplicates = %v, want none", findings)
+	}
+}
+
+func TestValidateResolvedSkipsDuplicates(t *testing.T) {
+	resolved := &ResolvedDailyLog{Day: "Monday", ActivityLog: "Sama.", LessonLearned: "Sama.", Obstacles: "Sama."}
+	rules := Rules{CheckDuplicates: true}
+	if findings := ValidateResolved(resolved, rules); len(findings) != 0 {
+		t.Errorf("findings = %v, want none", findings)
+	}
+}
+
+func TestCountSentences(t *testing.T) {
+	tests := map[string]int{
+		"":                      0,
+		"Satu kalimat":          1,
+		"Satu. Dua! Tiga?":      3,
+		"Versi 1.2 dirilis.":    2,
+		"Tunggu... lalu lanjut": 2,
+		"...":                   0,
+	}
+	for text, want := range tests {
+		if got := countSentences(text); got != want {
+			t.Errorf("countSentences(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		rules, err := LoadRules(filepath.Join(dir, "missing.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(rules, DefaultRules()) {
+			t.Errorf("rules = %+v, want the defaults", rules)
+		}
+	})
+
+	t.Run("overrides merge over the defaults", func(t *testing.T) {
+		path := write("rules.json", `{
+			"fields": {
+				"activity_log": {"max_runes": 500, "banned_phrases": ["lorem"]},
+				"obstacles": {"min_runes": 0}
+			}
+		}`)
+		rules, err := LoadRules(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := DefaultRules()
+		want.Fields["activity_log"] = FieldRules{MinRunes: 100, MaxRunes: 500, BannedPhrases: []string{"lorem"}}
+		want.Fields["obstacles"] = FieldRules{}
+		if !reflect.DeepEqual(rules, want) {
+			t.Errorf("rules = %+v, want %+v", rules, want)
+		}
+	})
+
+	t.Run("duplicates can be turned off", func(t *testing.T) {
+		rules, err := LoadRules(write("nodup.json", `{"check_duplicates": false}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rules.CheckDuplicates {
+			t.Error("CheckDuplicates = true, want false")
+		}
+		if rules.Fields["lesson_learned"].MinRunes != 100 {
+			t.Errorf("lesson_learned min_runes = %d, want the default 100", rules.Fields["lesson_learned"].MinRunes)
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		if _, err := LoadRules(write("unknown.json", `{"fields": {"notes": {"min_runes": 1}}}`)); err == nil {
+			t.Error("LoadRules accepted an unknown field")
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		if _, err := LoadRules(write("invalid.json", `{"fields":`)); err == nil {
+			t.Error("LoadRules accepted invalid JSON")
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Window is a daily time range, e.g. 07:45-08:30, in which each day's run
+// time is drawn. The draw is seeded by the date so it is stable across
+// restarts, and by Seed so different users do not share the same times
+type Window struct {
+	Start time.Duration
+	End   time.Duration
+	Seed  string
+}
+
+// ParseWindow parses "HH:MM-HH:MM". Windows crossing midnight are not supported
+func ParseWindow(spec, seed string) (*Window, error) {
+	from, to, ok := strings.Cut(spec, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid run window %q, expected HH:MM-HH:MM", spec)
+	}
+
+	start, err := parseClock(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run window %q: %w", spec, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run window %q: %w", spec, err)
+	}
+	if end < start {
+		return nil, fmt.Errorf("invalid run window %q: end is before start", spec)
+	}
+
+	return &Window{Start: start, End: end, Seed: seed}, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", strings.TrimSpace(value))
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// String formats the window as HH:MM-HH:MM
+func (w *Window) String() string {
+	return fmt.Sprintf("%s-%s", formatClock(w.Start), formatClock(w.End))
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// RunTime returns the drawn run time on the calendar day of day
+func (w *Window) RunTime(day time.Time) time.Time {
+	midnight := startOfDay(day)
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%s", w.Seed, midnight.Format(dateLayout))
+
+	span := uint64((w.End-w.Start)/time.Second) + 1
+	offset := time.Duration(h.Sum64()%span) * time.Second
+
+	return midnight.Add(w.Start + offset)
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// windowSchedule is a cron.Schedule that takes the days from a cron
+// expression and the time of day from a Window
+type windowSchedule struct {
+	days   cron.Schedule
+	window *Window
+}
+
+// maxScheduleDays bounds the search for the next matching day
+const maxScheduleDays = 5 * 366
+
+// Next implements cron.Schedule
+func (s windowSchedule) Next(t time.Time) time.Time {
+	day := startOfDay(t)
+	for i := 0; i < maxScheduleDays; i++ {
+		d := day.AddDate(0, 0, i)
+		if !s.firesOn(d) {
+			continue
+		}
+		if run := s.window.RunTime(d); run.After(t) {
+			return run
+		}
+	}
+	return time.Time{}
+}
+
+// firesOn reports whether the cron expression fires at any time on day
+func (s windowSchedule) firesOn(day time.Time) bool {
+	next := s.days.Next(day.Add(-time.Second))
+	return !next.IsZero() && next.Before(day.AddDate(0, 0, 1))
+}
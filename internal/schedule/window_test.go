@@ -0,0 +1,108 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"maganghub-autopresence/internal/api"
+
+	"github.com/robfig/cron/v3"
+)
+
+func TestParseWindow(t *testing.T) {
+	w, err := ParseWindow(" 07:45 - 08:30 ", "seed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Start != 7*time.Hour+45*time.Minute || w.End != 8*time.Hour+30*time.Minute || w.Seed != "seed" {
+		t.Errorf("ParseWindow = %+v", w)
+	}
+	if got := w.String(); got != "07:45-08:30" {
+		t.Errorf("String = %q, want 07:45-08:30", got)
+	}
+
+	for _, spec := range []string{"", "07:45", "7.45-08:30", "07:45-25:00", "23:00-01:00"} {
+		if _, err := ParseWindow(spec, ""); err == nil {
+			t.Errorf("ParseWindow(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestWindowRunTime(t *testing.T) {
+	w, err := ParseWindow("07:45-08:30", "user-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, api.Location)
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 60; i++ {
+		d := day.AddDate(0, 0, i)
+		run := w.RunTime(d)
+
+		start := d.Add(w.Start)
+		end := d.Add(w.End)
+		if run.Before(start) || run.After(end) {
+			t.Fatalf("RunTime(%s) = %s, outside %s", d.Format(time.DateOnly), run, w)
+		}
+		seen[run.Sub(d)] = true
+
+		// The draw depends on the date, not on the time of day asked
+		if later := w.RunTime(d.Add(13 * time.Hour)); !later.Equal(run) {
+			t.Errorf("RunTime at 13:00 = %s, at midnight = %s", later, run)
+		}
+	}
+	if len(seen) < 10 {
+		t.Errorf("only %d distinct run times in 60 days", len(seen))
+	}
+
+	other := &Window{Start: w.Start, End: w.End, Seed: "user-b"}
+	same := 0
+	for i := 0; i < 30; i++ {
+		d := day.AddDate(0, 0, i)
+		if w.RunTime(d).Equal(other.RunTime(d)) {
+			same++
+		}
+	}
+	if same > 3 {
+		t.Errorf("seeds user-a and user-b drew the same time on %d of 30 days", same)
+	}
+}
+
+func TestWindowRunTimeEmptyWindow(t *testing.T) {
+	w, err := ParseWindow("08:00-08:00", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, api.Location)
+	if got, want := w.RunTime(day), day.Add(8*time.Hour); !got.Equal(want) {
+		t.Errorf("RunTime = %s, want %s", got, want)
+	}
+}
+
+func TestWindowScheduleNext(t *testing.T) {
+	days, err := cron.ParseStandard("0 8 * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := ParseWindow("07:45-08:30", "seed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := windowSchedule{days: days, window: w}
+
+	friday := time.Date(2026, 10, 23, 0, 0, 0, 0, api.Location)
+	monday := friday.AddDate(0, 0, 3)
+
+	// Before Friday's draw it fires on Friday
+	if got, want := s.Next(friday), w.RunTime(friday); !got.Equal(want) {
+		t.Errorf("Next(Friday 00:00) = %s, want %s", got, want)
+	}
+	// After Friday's draw the weekend is skipped
+	if got, want := s.Next(w.RunTime(friday)), w.RunTime(monday); !got.Equal(want) {
+		t.Errorf("Next(Friday's run) = %s, want %s", got, want)
+	}
+	if got, want := s.Next(friday.Add(23*time.Hour)), w.RunTime(monday); !got.Equal(want) {
+		t.Errorf("Next(Friday 23:00) = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,240 @@
+package schedule
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAML parses a single YAML document and returns its root node, or nil
+// when the document is empty. Duplicate keys are rejected. Errors start
+// with "baris N: " when the line is known
+func parseYAML(data []byte) (*yaml.Node, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(escapeSlashes(data)))
+
+	var doc yaml.Node
+	if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+		return nil, nil
+	} else if err != nil {
+		return nil, yamlError(err)
+	}
+
+	var next yaml.Node
+	switch err := dec.Decode(&next); {
+	case err == nil:
+		return nil, fmt.Errorf("baris %d: hanya satu dokumen YAML yang didukung", next.Line)
+	case !errors.Is(err, io.EOF):
+		return nil, yamlError(err)
+	}
+
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if err := checkYAMLNode(root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// yamlErrorLine matches the position yaml.v3 puts in syntax errors
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// yamlError rewrites a yaml.v3 error as "baris N: ..."
+func yamlError(err error) error {
+	msg := err.Error()
+	if m := yamlErrorLine.FindStringSubmatch(msg); m != nil {
+		return fmt.Errorf("baris %s: %s", m[1], msg[len(m[0]):])
+	}
+	return errors.New(strings.TrimPrefix(msg, "yaml: "))
+}
+
+// checkYAMLNode restores escaped slashes in every scalar below n and
+// rejects duplicate mapping keys
+func checkYAMLNode(n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		restoreSlashes(n)
+	case yaml.MappingNode:
+		seen := make(map[string]bool)
+		for i, child := range n.Content {
+			if err := checkYAMLNode(child); err != nil {
+				return err
+			}
+			if i%2 == 1 {
+				continue
+			}
+			if seen[child.Value] {
+				return fmt.Errorf("baris %d: key %q duplikat", child.Line, child.Value)
+			}
+			seen[child.Value] = true
+		}
+	case yaml.SequenceNode:
+		for _, child := range n.Content {
+			if err := checkYAMLNode(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// slashPlaceholder replaces the \/ escape before parsing. YAML 1.2 allows
+// it in double-quoted scalars but yaml.v3 rejects it; restoreSlashes turns
+// the placeholder back into "/" there and into the text as written elsewhere
+const (
+	slashPlaceholder        = `\U0010FFFD`
+	slashPlaceholderDecoded = "\U0010FFFD"
+)
+
+// escapeSlashes replaces every \/ whose backslash is not itself escaped
+func escapeSlashes(data []byte) []byte {
+	if !bytes.Contains(data, []byte(`\/`)) {
+		return data
+	}
+
+	var b bytes.Buffer
+	backslashes := 0
+	for _, c := range data {
+		if c == '/' && backslashes%2 == 1 {
+			b.Truncate(b.Len() - 1)
+			b.WriteString(slashPlaceholder)
+			backslashes = 0
+			continue
+		}
+		if c == '\\' {
+			backslashes++
+		} else {
+			backslashes = 0
+		}
+		b.WriteByte(c)
+	}
+	return b.Bytes()
+}
+
+func restoreSlashes(n *yaml.Node) {
+	if n.Style&yaml.DoubleQuotedStyle != 0 {
+		n.Value = strings.ReplaceAll(n.Value, slashPlaceholderDecoded, "/")
+	} else {
+		n.Value = strings.ReplaceAll(n.Value, slashPlaceholder, `\/`)
+	}
+}
+
+// yamlTarget follows an alias to the node it refers to
+func yamlTarget(n *yaml.Node) *yaml.Node {
+	for n != nil && n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// isYAMLNull reports whether n is missing, empty or null
+func isYAMLNull(n *yaml.Node) bool {
+	return n == nil || n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null"
+}
+
+// yamlLookup returns the value of key in mapping n, or nil
+func yamlLookup(n *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return yamlTarget(n.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// decodeYAMLDoc decodes a parsed daily log document of the same shape as
+// the JSON file, except that a field may hold one string instead of a list
+func decodeYAMLDoc(root *yaml.Node) (dailyLogsByDay, error) {
+	var byDay dailyLogsByDay
+	root = yamlTarget(root)
+	if isYAMLNull(root) {
+		return byDay, nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return byDay, fmt.Errorf("baris %d: dokumen harus berupa mapping dengan key days", root.Line)
+	}
+
+	days := yamlLookup(root, "days")
+	if isYAMLNull(days) {
+		return byDay, nil
+	}
+	if days.Kind != yaml.MappingNode {
+		return byDay, fmt.Errorf("baris %d: days harus berupa mapping", days.Line)
+	}
+
+	byDay.Days = make(map[string]dailyLogEntry)
+	for i := 0; i+1 < len(days.Content); i += 2 {
+		entry, err := decodeYAMLEntry(days.Content[i+1])
+		if err != nil {
+			return dailyLogsByDay{}, err
+		}
+		byDay.Days[days.Content[i].Value] = entry
+	}
+	return byDay, nil
+}
+
+// decodeYAMLEntry decodes one day's fields. Unknown keys are ignored, as
+// they are in JSON
+func decodeYAMLEntry(n *yaml.Node) (dailyLogEntry, error) {
+	var entry dailyLogEntry
+	n = yamlTarget(n)
+	if isYAMLNull(n) {
+		return entry, nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return entry, fmt.Errorf("baris %d: isi hari harus berupa mapping field", n.Line)
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		field := n.Content[i].Value
+		if !isLogField(field) {
+			continue
+		}
+		variants, err := yamlStrings(field, n.Content[i+1])
+		if err != nil {
+			return dailyLogEntry{}, err
+		}
+		switch field {
+		case "activity_log":
+			entry.ActivityLog = variants
+		case "lesson_learned":
+			entry.LessonLearned = variants
+		case "obstacles":
+			entry.Obstacles = variants
+		}
+	}
+	return entry, nil
+}
+
+// yamlStrings decodes a field holding one string or a list of strings.
+// Block scalars end in a newline that is not part of the log
+func yamlStrings(field string, n *yaml.Node) ([]string, error) {
+	n = yamlTarget(n)
+	switch {
+	case isYAMLNull(n):
+		return nil, nil
+	case n.Kind == yaml.ScalarNode:
+		return []string{strings.TrimRight(n.Value, "\n")}, nil
+	case n.Kind == yaml.SequenceNode:
+		values := make([]string, 0, len(n.Content))
+		for i, item := range n.Content {
+			item = yamlTarget(item)
+			switch {
+			case isYAMLNull(item):
+				values = append(values, "")
+			case item.Kind == yaml.ScalarNode:
+				values = append(values, strings.TrimRight(item.Value, "\n"))
+			default:
+				return nil, fmt.Errorf("baris %d: %s[%d] harus berupa teks; apit teks yang mengandung \": \" dengan tanda kutip", item.Line, field, i)
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("baris %d: %s harus berupa teks atau list teks", n.Line, field)
+}
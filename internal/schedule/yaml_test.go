@@ -0,0 +1,224 @@
+package schedule
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlValue converts a node into map[string]any, []any, string and nil
+// values for comparison
+func yamlValue(n *yaml.Node) any {
+	n = yamlTarget(n)
+	switch {
+	case isYAMLNull(n):
+		return nil
+	case n.Kind == yaml.MappingNode:
+		m := make(map[string]any)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			m[n.Content[i].Value] = yamlValue(n.Content[i+1])
+		}
+		return m
+	case n.Kind == yaml.SequenceNode:
+		items := []any{}
+		for _, item := range n.Content {
+			items = append(items, yamlValue(item))
+		}
+		return items
+	}
+	return n.Value
+}
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want any
+	}{
+		{
+			name: "nested mapping and sequence",
+			in: `
+days:
+  Monday:
+    activity_log:
+      - first
+      - second
+`,
+			want: map[string]any{"days": map[string]any{"Monday": map[string]any{
+				"activity_log": []any{"first", "second"},
+			}}},
+		},
+		{
+			name: "sequence at the key's indentation",
+			in: `
+obstacles:
+- one
+- two
+`,
+			want: map[string]any{"obstacles": []any{"one", "two"}},
+		},
+		{
+			name: "plain scalar continues on indented lines",
+			in: `
+text: first line
+  continues here
+
+  after a blank line
+other: x
+`,
+			want: map[string]any{"text": "first line continues here\nafter a blank line", "other": "x"},
+		},
+		{
+			name: "quoted scalars and comments",
+			in: `
+---
+# a comment
+a: "colon: inside" # trailing comment
+b: 'it''s'
+c: "tab\tescape"
+'quoted key': value
+`,
+			want: map[string]any{"a": "colon: inside", "b": "it's", "c": "tab\tescape", "quoted key": "value"},
+		},
+		{
+			name: "hash inside a word is not a comment",
+			in:   "tag: C#sharp and issue#12\n",
+			want: map[string]any{"tag": "C#sharp and issue#12"},
+		},
+		{
+			name: "literal block",
+			in: `
+log: |
+  line one
+  line two
+
+next: x
+`,
+			want: map[string]any{"log": "line one\nline two\n", "next": "x"},
+		},
+		{
+			name: "literal block, strip and keep chomping",
+			in:   "strip: |-\n  a\n  b\n\nkeep: |+\n  c\n\n",
+			want: map[string]any{"strip": "a\nb", "keep": "c\n\n"},
+		},
+		{
+			name: "folded block",
+			in: `
+log: >
+  folded
+  into one
+
+  new paragraph
+    more indented
+`,
+			want: map[string]any{"log": "folded into one\nnew paragraph\n  more indented\n"},
+		},
+		{
+			name: "block indentation indicator",
+			in:   "a: |2\n   x\n",
+			want: map[string]any{"a": " x\n"},
+		},
+		{
+			name: "byte order mark",
+			in:   "\ufeffa: b\n",
+			want: map[string]any{"a": "b"},
+		},
+		{
+			name: "double-quoted scalar over several lines",
+			in:   "a: \"first\n  second\"\n",
+			want: map[string]any{"a": "first second"},
+		},
+		{
+			name: "escaped slash",
+			in:   `a: "x\/y \\/z"` + "\n" + `b: plain\/text` + "\n" + "c: |\n  block\\/text\n",
+			want: map[string]any{"a": `x/y \/z`, "b": `plain\/text`, "c": "block\\/text\n"},
+		},
+		{
+			name: "anchors and aliases",
+			in:   "a: &text shared\nb: *text\n",
+			want: map[string]any{"a": "shared", "b": "shared"},
+		},
+		{
+			name: "flow sequence",
+			in:   `items: [a, "b, c", 'd']` + "\n",
+			want: map[string]any{"items": []any{"a", "b, c", "d"}},
+		},
+		{
+			name: "empty flow sequence and null",
+			in:   "items: []\nnothing: ~\n",
+			want: map[string]any{"items": []any{}, "nothing": nil},
+		},
+		{
+			name: "CRLF line endings",
+			in:   "a: 1\r\nb:\r\n  - x\r\n",
+			want: map[string]any{"a": "1", "b": []any{"x"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := parseYAML([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("parseYAML: %v", err)
+			}
+			if got := yamlValue(root); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAML = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"tab indentation", "a:\n\t- x\n", "baris 2: found character that cannot start any token"},
+		{"duplicate key", "a: 1\na: 2\n", `baris 2: key "a" duplikat`},
+		{"duplicate nested key", "days:\n  Monday: {}\n  Monday: {}\n", `baris 3: key "Monday" duplikat`},
+		{"unexpected indentation", "a:\n    b: 1\n  c: 2\n", "baris 2: did not find expected key"},
+		{"unclosed quote", `a: "open` + "\n", "baris 2: found unexpected end of stream"},
+		{"unclosed flow sequence", "a: [x, y\n", "baris 1: did not find expected ',' or ']'"},
+		{"multiple documents", "a: 1\n---\nb: 2\n", "baris 2: hanya satu dokumen"},
+		{"unknown escape", `a: "\q"` + "\n", "found unknown escape character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseYAML([]byte(tt.in))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseYAML error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeYAMLDocErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"mapping in a list", "days:\n  Monday:\n    activity_log:\n      - key: value\n", "baris 4: activity_log[0] harus berupa teks"},
+		{"flow mapping", "days:\n  Monday:\n    obstacles: {a: b}\n", "baris 3: obstacles harus berupa teks atau list teks"},
+		{"day is not a mapping", "days:\n  Monday: text\n", "baris 2: isi hari harus berupa mapping field"},
+		{"days is a list", "days: [Monday]\n", "baris 1: days harus berupa mapping"},
+		{"root is a list", "- days\n", "baris 1: dokumen harus berupa mapping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := parseYAML([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("parseYAML: %v", err)
+			}
+			_, err = decodeYAMLDoc(root)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("decodeYAMLDoc error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}